@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"iFall/internal/bot"
 	"iFall/internal/client"
 	"iFall/internal/config"
@@ -9,6 +10,7 @@ import (
 	"iFall/internal/domain/repositories"
 	"iFall/internal/domain/services"
 	"iFall/internal/email"
+	"iFall/internal/notifier"
 	"iFall/internal/scheduler"
 	"iFall/pkg/logger"
 	"iFall/pkg/server"
@@ -41,15 +43,23 @@ func Run() {
 		logger.Info("server closed successfully")
 	}()
 
-	client := client.NewClient(cfg.ApiClient)
+	client := client.NewClient(cfg.ApiClient, client.MustRuleSources(cfg.ApiClient.Sources)...)
+	logger.Info("scraping rules loaded successfully", "sources", len(cfg.ApiClient.Sources))
 
 	smtpAuth := smtp.PlainAuth("", cfg.Email.Address, cfg.Email.Password, cfg.Email.SmtpAddress)
 	emailSender := email.NewEmailSender(smtpAuth, cfg.Email)
 
 	userRepository := repositories.NewUserRepository(storage)
 	iphoneRepository := repositories.NewIPhoneRepository(storage)
-
-	bot := bot.NewTelegramBot(cfg.TelegramBot, logger, userRepository)
+	priceHistoryRepository := repositories.NewPriceHistoryRepository(storage)
+	offerRepository := repositories.NewOfferRepository(storage)
+	stockSubscriptionRepository := repositories.NewStockSubscriptionRepository(storage)
+	alertRepository := repositories.NewAlertRepository(storage)
+	outboxRepository := repositories.NewOutboxRepository(storage)
+	channelRepository := repositories.NewChannelRepository(storage)
+	chatStateRepository := repositories.NewChatStateRepository(storage)
+
+	bot := bot.NewTelegramBot(cfg.TelegramBot, logger, userRepository, iphoneRepository, priceHistoryRepository, offerRepository, stockSubscriptionRepository, alertRepository, chatStateRepository)
 	logger.Info("bot created successfully")
 	bot.SetupTelegramBot()
 	defer func() {
@@ -57,17 +67,39 @@ func Run() {
 		logger.Info("bot stopped successfully")
 	}()
 
-	userService := services.NewUserService(userRepository, logger)
-
-	iphoneService := services.NewIPhoneService(iphoneRepository, client, logger, emailSender, cfg.IPhones)
-	iphoneReportService := services.NewIPhoneReportService(userRepository, logger, bot, emailSender, cfg.IPhones)
+	userService := services.NewUserService(userRepository, logger, cfg.TelegramBot)
+	alertService := services.NewAlertService(alertRepository, iphoneRepository, priceHistoryRepository, logger)
+
+	iphoneService := services.NewIPhoneService(iphoneRepository, priceHistoryRepository, offerRepository, stockSubscriptionRepository, client, logger, emailSender, cfg.IPhones)
+	notifiers := notifier.NewRegistry(
+		notifier.NewEmailNotifier(emailSender),
+		notifier.NewTelegramNotifier(bot),
+		notifier.NewSlackNotifier(notifier.NewPublicClient(), cfg.Chat),
+		notifier.NewDiscordNotifier(notifier.NewPublicClient(), cfg.Chat),
+		notifier.NewMattermostNotifier(notifier.NewPublicClient(), cfg.Chat),
+	)
+	// without a secret the webhook channel is not offered, so the api rejects new webhook channels
+	webhookEnabled, err := notifier.WebhookEnabled(cfg.Webhook)
+	if err != nil {
+		panic(fmt.Errorf("invalid webhook config: %w", err))
+	}
+	if webhookEnabled {
+		notifiers.Register(notifier.NewWebhookNotifier(notifier.NewPublicClient(), cfg.Webhook))
+	}
+	channelService := services.NewChannelService(channelRepository, notifiers, logger)
+	outboxService := services.NewOutboxService(outboxRepository, notifiers, logger, cfg.Outbox)
+	iphoneReportService := services.NewIPhoneReportService(channelRepository, stockSubscriptionRepository, alertService, outboxService, notifiers, logger, cfg.IPhones)
 
 	userHandler := handlers.NewUsersHandler(userService, validator)
+	iphonesHandler := handlers.NewIPhonesHandler(iphoneService, validator)
+	alertsHandler := handlers.NewAlertsHandler(alertService, validator)
+	channelsHandler := handlers.NewChannelsHandler(channelService, validator)
+	telegramHandler := handlers.NewTelegramHandler(bot, cfg.TelegramBot)
 
-	routesSetup := routes.NewRoutesSetup(server.App, userHandler)
+	routesSetup := routes.NewRoutesSetup(server.App, userHandler, iphonesHandler, alertsHandler, channelsHandler, telegramHandler)
 	routesSetup.SetupRoutes()
 
-	scheduler := scheduler.NewScheduler(iphoneService, iphoneReportService, logger, cfg.Scheduler)
+	scheduler := scheduler.NewScheduler(iphoneService, iphoneReportService, outboxService, logger, cfg.Scheduler, cfg.Outbox)
 	scheduler.Start()
 	defer func() {
 		scheduler.Stop()
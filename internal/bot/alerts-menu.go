@@ -0,0 +1,387 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+// wizardSteps are the thresholds offered by the new alert wizard, in percent below the current price.
+var wizardSteps = []float64{3, 5, 10, 15}
+
+func (tb *telegramBot) alertsMenu() {
+	op := place + "alertsMenu"
+	log := tb.Logger.AddOp(op)
+	menu := telebot.Btn{Unique: "alert_menu"}
+	pause := telebot.Btn{Unique: "alert_pause"}
+	edit := telebot.Btn{Unique: "alert_edit"}
+	set := telebot.Btn{Unique: "alert_set"}
+	remove := telebot.Btn{Unique: "alert_delete"}
+	create := telebot.Btn{Unique: "alert_new"}
+	pickModel := telebot.Btn{Unique: "alert_new_model"}
+	pickColor := telebot.Btn{Unique: "alert_new_color"}
+	pickPrice := telebot.Btn{Unique: "alert_new_price"}
+
+	// send shows the menu in place of the message the pressed button belongs to.
+	send := func(c telebot.Context, header string) error {
+		chatId := c.Chat().ID
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		alerts, err := tb.AlertRepository.FetchByUser(ctx, models.Contacts{ChatId: &chatId})
+		if err != nil {
+			log.Error("failed to fetch alerts", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		markup := &telebot.ReplyMarkup{}
+		rows := []telebot.Row{}
+		lines := []string{"🔔 ваши алерты:"}
+		for _, alert := range alerts {
+			id := strconv.FormatInt(alert.Id, 10)
+			toggle := markup.Data("⏸ #"+id, pause.Unique, id, "1")
+			if alert.Paused {
+				toggle = markup.Data("▶️ #"+id, pause.Unique, id, "0")
+			}
+			row := telebot.Row{toggle}
+			if len(thresholdOptions(alert)) > 0 {
+				row = append(row, markup.Data("✏️ #"+id, edit.Unique, id))
+			}
+			row = append(row, markup.Data("🗑 #"+id, remove.Unique, id))
+			rows = append(rows, row)
+			lines = append(lines, fmt.Sprintf("#%d %s", alert.Id, alertTitle(alert)))
+		}
+		rows = append(rows, markup.Row(markup.Data("➕ новый алерт", create.Unique)))
+		markup.Inline(rows...)
+		msg := strings.Join(lines, "\n")
+		if len(alerts) == 0 {
+			msg = "алертов пока нет, создайте первый кнопкой ниже или через /alert"
+		}
+		if header != "" {
+			msg = header + "\n\n" + msg
+		}
+		if c.Callback() != nil {
+			return c.Edit(msg, markup)
+		}
+		return c.Send(msg, markup)
+	}
+	alertId := func(c telebot.Context) (int64, string, bool) {
+		args := c.Args()
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return 0, "", false
+		}
+		if len(args) > 1 {
+			return id, args[1], true
+		}
+		return id, "", true
+	}
+
+	tb.Bot.Handle("/alerts", func(c telebot.Context) error {
+		return send(c, "")
+	})
+	tb.Bot.Handle(&menu, func(c telebot.Context) error {
+		return send(c, "")
+	})
+	tb.Bot.Handle(&pause, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		id, flag, ok := alertId(c)
+		if !ok {
+			return c.Edit("произошла ошибка((")
+		}
+		paused := flag == "1"
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		if err := tb.AlertRepository.SetPaused(ctx, id, paused, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return send(c, "алерт уже удален")
+			}
+			log.Error("failed to pause alert", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if paused {
+			return send(c, fmt.Sprintf("⏸ алерт #%d на паузе", id))
+		}
+		return send(c, fmt.Sprintf("▶️ алерт #%d снова работает", id))
+	})
+	tb.Bot.Handle(&remove, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		id, _, ok := alertId(c)
+		if !ok {
+			return c.Edit("произошла ошибка((")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		if err := tb.AlertRepository.Delete(ctx, id, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return send(c, "алерт уже удален")
+			}
+			log.Error("failed to delete alert", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		return send(c, fmt.Sprintf("🗑 алерт #%d удален", id))
+	})
+	tb.Bot.Handle(&edit, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		id, _, ok := alertId(c)
+		if !ok {
+			return c.Edit("произошла ошибка((")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		alerts, err := tb.AlertRepository.FetchByUser(ctx, models.Contacts{ChatId: &chatId})
+		if err != nil {
+			log.Error("failed to fetch alerts", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		i := slices.IndexFunc(alerts, func(a models.Alert) bool { return a.Id == id })
+		if i < 0 {
+			return send(c, "алерт уже удален")
+		}
+		alert := alerts[i]
+		markup := &telebot.ReplyMarkup{}
+		buttons := []telebot.Btn{}
+		for _, value := range thresholdOptions(alert) {
+			buttons = append(buttons, markup.Data(formatThreshold(alert.Kind, value), set.Unique, strconv.FormatInt(id, 10), strconv.FormatFloat(value, 'f', -1, 64)))
+		}
+		rows := markup.Split(2, buttons)
+		rows = append(rows, markup.Row(markup.Data("⬅️ назад", menu.Unique)))
+		markup.Inline(rows...)
+		return c.Edit(fmt.Sprintf("✏️ алерт #%d: %s\nвыберите новый порог:", id, alertTitle(alert)), markup)
+	})
+	tb.Bot.Handle(&set, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		id, raw, ok := alertId(c)
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if !ok || err != nil || threshold <= 0 {
+			return c.Edit("произошла ошибка((")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		if err := tb.AlertRepository.UpdateThreshold(ctx, id, threshold, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return send(c, "алерт уже удален")
+			}
+			log.Error("failed to update alert threshold", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		return send(c, fmt.Sprintf("✅ новый порог алерта #%d: %s", id, raw))
+	})
+
+	tb.Bot.Handle(&create, func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		markup := &telebot.ReplyMarkup{}
+		buttons := []telebot.Btn{}
+		for _, model := range distinct(iphones, func(iphone models.IPhone) string { return iphone.Model }) {
+			if !wizardFits(pickPrice.Unique, iphones, model, "") {
+				continue
+			}
+			buttons = append(buttons, markup.Data(model, pickModel.Unique, model))
+		}
+		rows := markup.Split(2, buttons)
+		rows = append(rows, markup.Row(markup.Data("любая модель", pickModel.Unique, "")), markup.Row(markup.Data("⬅️ назад", menu.Unique)))
+		markup.Inline(rows...)
+		return c.Edit("➕ новый алерт\nвыберите модель:", markup)
+	})
+	tb.Bot.Handle(&pickModel, func(c telebot.Context) error {
+		model := c.Args()[0]
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		iphones = matchIPhones(iphones, model, "")
+		markup := &telebot.ReplyMarkup{}
+		buttons := []telebot.Btn{}
+		for _, color := range distinct(iphones, func(iphone models.IPhone) string { return iphone.ColorName }) {
+			if !wizardFits(pickPrice.Unique, iphones, model, color) {
+				continue
+			}
+			buttons = append(buttons, markup.Data(color, pickColor.Unique, model, color))
+		}
+		rows := markup.Split(3, buttons)
+		rows = append(rows, markup.Row(markup.Data("любой цвет", pickColor.Unique, model, "")), markup.Row(markup.Data("⬅️ назад", create.Unique)))
+		markup.Inline(rows...)
+		return c.Edit(fmt.Sprintf("➕ новый алерт · %s\nвыберите цвет:", productTitle(model, "")), markup)
+	})
+	tb.Bot.Handle(&pickColor, func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) != 2 {
+			return c.Edit("произошла ошибка((")
+		}
+		model, color := args[0], args[1]
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		markup := &telebot.ReplyMarkup{}
+		back := markup.Row(markup.Data("⬅️ назад", pickModel.Unique, model))
+		current, ok := lowestPrice(matchIPhones(iphones, model, color))
+		if !ok {
+			markup.Inline(back)
+			return c.Edit(fmt.Sprintf("сейчас нет цен на %s, выберите другой цвет", productTitle(model, color)), markup)
+		}
+		buttons := []telebot.Btn{}
+		for i, value := range wizardThresholds(current) {
+			raw := strconv.FormatFloat(value, 'f', -1, 64)
+			if !fitsCallback(pickPrice.Unique, model, color, raw) {
+				continue
+			}
+			text := fmt.Sprintf("ниже %.0f (-%.0f%%)", value, wizardSteps[i])
+			buttons = append(buttons, markup.Data(text, pickPrice.Unique, model, color, raw))
+		}
+		rows := markup.Split(2, buttons)
+		rows = append(rows, back)
+		markup.Inline(rows...)
+		return c.Edit(fmt.Sprintf("➕ новый алерт · %s\nсейчас от %.2f, выберите порог:", productTitle(model, color), current), markup)
+	})
+	tb.Bot.Handle(&pickPrice, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		args := c.Args()
+		if len(args) != 3 {
+			return c.Edit("произошла ошибка((")
+		}
+		threshold, err := strconv.ParseFloat(args[2], 64)
+		if err != nil || threshold <= 0 {
+			return c.Edit("произошла ошибка((")
+		}
+		alert := &models.Alert{
+			Model:     args[0],
+			ColorName: args[1],
+			Kind:      models.AlertKindPrice,
+			Direction: models.AlertDirectionBelow,
+			Threshold: threshold,
+			Repeat:    models.AlertRepeatRearm,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		if err := tb.AlertRepository.Create(ctx, alert, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Edit("сначала зарегистрируйтесь через /start")
+			}
+			log.Error("failed to create alert", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		return send(c, fmt.Sprintf("✅ алерт #%d создан: %s", alert.Id, alertTitle(*alert)))
+	})
+}
+
+// thresholdOptions offers thresholds around the current one, percent rules move by points and price rules by percent.
+func thresholdOptions(alert models.Alert) []float64 {
+	options := []float64{}
+	add := func(value float64) {
+		if value > 0 && value != alert.Threshold && !slices.Contains(options, value) {
+			options = append(options, value)
+		}
+	}
+	switch alert.Kind {
+	case models.AlertKindLow:
+		return nil
+	case models.AlertKindDiscount, models.AlertKindDrop:
+		for _, step := range []float64{-5, -2, 2, 5} {
+			if value := alert.Threshold + step; value < 100 {
+				add(value)
+			}
+		}
+	default:
+		for _, factor := range []float64{0.9, 0.95, 1.05, 1.1} {
+			add(roundPrice(alert.Threshold * factor))
+		}
+	}
+	return options
+}
+
+// wizardFits checks the threshold buttons the choice leads to, they carry the longest callback data of the wizard.
+func wizardFits(unique string, iphones []models.IPhone, model, color string) bool {
+	current, ok := lowestPrice(matchIPhones(iphones, model, color))
+	if !ok {
+		return fitsCallback(unique, model, color)
+	}
+	for _, value := range wizardThresholds(current) {
+		if !fitsCallback(unique, model, color, strconv.FormatFloat(value, 'f', -1, 64)) {
+			return false
+		}
+	}
+	return true
+}
+
+func wizardThresholds(price float64) []float64 {
+	thresholds := make([]float64, 0, len(wizardSteps))
+	for _, step := range wizardSteps {
+		thresholds = append(thresholds, roundPrice(price*(1-step/100)))
+	}
+	return thresholds
+}
+
+func roundPrice(price float64) float64 {
+	if price >= 100 {
+		return math.Round(price/10) * 10
+	}
+	return math.Round(price)
+}
+
+func formatThreshold(kind string, value float64) string {
+	if kind == models.AlertKindDiscount || kind == models.AlertKindDrop {
+		return strconv.FormatFloat(value, 'f', -1, 64) + "%"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func matchIPhones(iphones []models.IPhone, model, color string) []models.IPhone {
+	matched := []models.IPhone{}
+	for _, iphone := range iphones {
+		if (model == "" || iphone.Model == model) && (color == "" || iphone.ColorName == color) {
+			matched = append(matched, iphone)
+		}
+	}
+	return matched
+}
+
+func lowestPrice(iphones []models.IPhone) (float64, bool) {
+	lowest := 0.0
+	for _, iphone := range iphones {
+		if iphone.Price > 0 && (lowest == 0 || iphone.Price < lowest) {
+			lowest = iphone.Price
+		}
+	}
+	return lowest, lowest > 0
+}
+
+// distinct keeps the catalog order of the values.
+func distinct(iphones []models.IPhone, value func(models.IPhone) string) []string {
+	values := []string{}
+	for _, iphone := range iphones {
+		if v := value(iphone); v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func productTitle(model, color string) string {
+	if model == "" {
+		model = "любой айфон"
+	}
+	if color != "" {
+		return model + " " + color
+	}
+	return model
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"iFall/internal/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdOptions(t *testing.T) {
+	tests := []struct {
+		testName        string
+		alert           models.Alert
+		expectedOptions []float64
+	}{
+		{
+			testName:        "price moves by percent",
+			alert:           models.Alert{Kind: models.AlertKindPrice, Threshold: 2800},
+			expectedOptions: []float64{2520, 2660, 2940, 3080},
+		},
+		{
+			testName:        "discount moves by points",
+			alert:           models.Alert{Kind: models.AlertKindDiscount, Threshold: 10},
+			expectedOptions: []float64{5, 8, 12, 15},
+		},
+		{
+			testName:        "drop stays in range",
+			alert:           models.Alert{Kind: models.AlertKindDrop, Threshold: 3},
+			expectedOptions: []float64{1, 5, 8},
+		},
+		{
+			testName:        "low has no threshold",
+			alert:           models.Alert{Kind: models.AlertKindLow},
+			expectedOptions: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOptions, thresholdOptions(tt.alert))
+		})
+	}
+}
+
+func TestWizard(t *testing.T) {
+	iphones := []models.IPhone{
+		{Model: "iPhone 17", ColorName: "Black", Price: 2800},
+		{Model: "iPhone 17", ColorName: "Green", Price: 2750},
+		{Model: "iPhone 17 Pro", ColorName: "Black", Price: 0},
+		{Model: "iPhone 17 Pro", ColorName: "Orange", Price: 4100},
+	}
+	assert.Equal(t, []string{"iPhone 17", "iPhone 17 Pro"}, distinct(iphones, func(iphone models.IPhone) string { return iphone.Model }))
+	assert.Equal(t, []string{"Black", "Orange"}, distinct(matchIPhones(iphones, "iPhone 17 Pro", ""), func(iphone models.IPhone) string { return iphone.ColorName }))
+
+	price, ok := lowestPrice(matchIPhones(iphones, "", "Black"))
+	assert.True(t, ok)
+	assert.Equal(t, 2800.0, price)
+	_, ok = lowestPrice(matchIPhones(iphones, "iPhone 17 Pro", "Black"))
+	assert.False(t, ok)
+
+	assert.Equal(t, []float64{2720, 2660, 2520, 2380}, wizardThresholds(price))
+}
+
+func TestWizardFits(t *testing.T) {
+	long := strings.Repeat("Cosmic ", 5) + "Orange"
+	iphones := []models.IPhone{
+		{Model: "iPhone 17 Pro Max", ColorName: "Cosmic Orange", Price: 5100},
+		{Model: "iPhone 17 Pro Max", ColorName: long, Price: 5100},
+		{Model: "iPhone 17 Pro Max", ColorName: "Red|Blue", Price: 5100},
+	}
+	tests := []struct {
+		testName string
+		model    string
+		color    string
+		expected bool
+	}{
+		{testName: "any color", model: "iPhone 17 Pro Max", expected: true},
+		{testName: "short color", model: "iPhone 17 Pro Max", color: "Cosmic Orange", expected: true},
+		{testName: "color past the limit", model: "iPhone 17 Pro Max", color: long, expected: false},
+		{testName: "color with separator", model: "iPhone 17 Pro Max", color: "Red|Blue", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, wizardFits("alert_new_price", iphones, tt.model, tt.color))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+const alertUsage = `напиши порог и, если нужно, модель, цвет и память:
+/alert 2800 — любой айфон дешевле 2800
+/alert <2800 17 pro green 256 — конкретный айфон дешевле 2800
+/alert >3500 17 — айфон 17 дороже 3500
+/alert 10% black — скидка на черный от 10%
+/alert drop 5% 24h — цена упала на 5% за сутки
+/alert low 30d 17 — минимум за 30 дней на айфон 17
+/alert change 100 — цена изменилась больше чем на 100 (+100 только рост, -100 только падение)
+
+по умолчанию алерт приходит один раз и снова включается, когда условие перестает выполняться.
+добавь в конец once — чтобы получить его только один раз, или every=6h — чтобы напоминать не чаще раза в 6 часов`
+
+var (
+	errInvalidThreshold = errors.New("invalid threshold")
+	errUnknownModel     = errors.New("unknown model")
+	errInvalidRepeat    = errors.New("invalid repeat")
+)
+
+func (tb *telegramBot) manageAlerts() {
+	op := place + "manageAlerts"
+	log := tb.Logger.AddOp(op)
+	tb.Bot.Handle("/alert", func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) == 0 {
+			return c.Send(alertUsage)
+		}
+		chatId := c.Chat().ID
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		alert, err := parseAlert(args, iphones)
+		if err != nil {
+			if errors.Is(err, errUnknownModel) {
+				return c.Send("❌ не знаю такой модели\n\n" + alertUsage)
+			}
+			if errors.Is(err, errInvalidRepeat) {
+				return c.Send("❌ неправильный интервал повтора\n\n" + alertUsage)
+			}
+			return c.Send("❌ неправильный формат порога\n\n" + alertUsage)
+		}
+		if err := tb.AlertRepository.Create(ctx, alert, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Send("сначала на обновления подпишитесь")
+			}
+			log.Error("failed to create alert", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		return c.Send(fmt.Sprintf("✅ алерт #%d создан: %s\nуправлять алертами: /alerts", alert.Id, alertTitle(*alert)))
+	})
+}
+
+func parseAlert(args []string, iphones []models.IPhone) (*models.Alert, error) {
+	alert := &models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Repeat: models.AlertRepeatRearm}
+	args, err := parseRepeat(alert, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errInvalidThreshold
+	}
+	threshold, rest := args[0], args[1:]
+	switch strings.ToLower(args[0]) {
+	case "drop", "падение":
+		alert.Kind = models.AlertKindDrop
+		alert.WindowHours = 24
+	case "low", "минимум":
+		alert.Kind = models.AlertKindLow
+		alert.WindowHours = 30 * 24
+		threshold = ""
+	case "change", "изменение":
+		alert.Kind = models.AlertKindChange
+		alert.Direction = models.AlertDirectionAny
+	}
+	if alert.Kind == models.AlertKindDrop || alert.Kind == models.AlertKindChange {
+		if len(rest) == 0 {
+			return nil, errInvalidThreshold
+		}
+		threshold, rest = rest[0], rest[1:]
+	}
+	if threshold != "" {
+		if err := parseThreshold(alert, threshold); err != nil {
+			return nil, err
+		}
+	}
+	if alert.WindowHours > 0 {
+		filtered := []string{}
+		for _, arg := range rest {
+			if hours, ok := parseWindow(arg); ok {
+				alert.WindowHours = hours
+				continue
+			}
+			filtered = append(filtered, arg)
+		}
+		rest = filtered
+	}
+
+	p, err := parseProduct(rest, iphones)
+	if err != nil {
+		return nil, err
+	}
+	alert.Model, alert.ColorName, alert.Capacity = p.Model, p.ColorName, p.Capacity
+	return alert, nil
+}
+
+type product struct {
+	Model     string
+	ColorName string
+	Capacity  int
+}
+
+// parseProduct picks color and capacity out of args and treats the remaining words as the model name.
+func parseProduct(args []string, iphones []models.IPhone) (product, error) {
+	p := product{}
+	colors := map[string]string{}
+	capacities := map[int]bool{}
+	for _, iphone := range iphones {
+		colors[strings.ToLower(iphone.ColorName)] = iphone.ColorName
+		capacities[iphone.Capacity] = true
+	}
+	words := []string{}
+	for i := 0; i < len(args); i++ {
+		if color, n := matchColor(args[i:], colors); n > 0 {
+			p.ColorName = color
+			i += n - 1
+			continue
+		}
+		token := strings.ToLower(args[i])
+		capacity, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(token, "gb"), "гб"))
+		if err == nil && capacities[capacity] {
+			p.Capacity = capacity
+			continue
+		}
+		words = append(words, args[i])
+	}
+	if len(words) > 0 {
+		model := strings.Join(words, " ")
+		for _, iphone := range iphones {
+			if strings.EqualFold(iphone.Model, model) || strings.EqualFold(iphone.Model, "iPhone "+model) {
+				p.Model = iphone.Model
+				break
+			}
+		}
+		if p.Model == "" {
+			return p, errUnknownModel
+		}
+	}
+	return p, nil
+}
+
+// matchColor finds the longest color at the start of args, so "cosmic orange" is one color and not a model.
+func matchColor(args []string, colors map[string]string) (string, int) {
+	for n := len(args); n > 0; n-- {
+		if color, ok := colors[strings.ToLower(strings.Join(args[:n], " "))]; ok && color != "" {
+			return color, n
+		}
+	}
+	return "", 0
+}
+
+func parseRepeat(alert *models.Alert, args []string) ([]string, error) {
+	filtered := []string{}
+	for _, arg := range args {
+		token := strings.ToLower(arg)
+		switch {
+		case token == "once" || token == "однажды":
+			alert.Repeat = models.AlertRepeatOnce
+		case token == "rearm":
+			alert.Repeat = models.AlertRepeatRearm
+		case strings.HasPrefix(token, "every="):
+			hours, ok := parseWindow(strings.TrimPrefix(token, "every="))
+			if !ok {
+				return nil, errInvalidRepeat
+			}
+			alert.Repeat = models.AlertRepeatCooldown
+			alert.CooldownHours = hours
+		default:
+			filtered = append(filtered, arg)
+		}
+	}
+	return filtered, nil
+}
+
+func parseThreshold(alert *models.Alert, threshold string) error {
+	threshold = strings.ReplaceAll(threshold, ",", ".")
+	switch {
+	case alert.Kind == models.AlertKindDrop:
+		threshold = strings.TrimPrefix(strings.TrimSuffix(threshold, "%"), "-")
+	case alert.Kind == models.AlertKindChange && strings.HasPrefix(threshold, "+"):
+		alert.Direction = models.AlertDirectionAbove
+		threshold = strings.TrimPrefix(threshold, "+")
+	case alert.Kind == models.AlertKindChange && strings.HasPrefix(threshold, "-"):
+		threshold = strings.TrimPrefix(threshold, "-")
+		alert.Direction = models.AlertDirectionBelow
+	case alert.Kind == models.AlertKindChange:
+	case strings.HasSuffix(threshold, "%"):
+		alert.Kind = models.AlertKindDiscount
+		alert.Direction = models.AlertDirectionAbove
+		threshold = strings.TrimPrefix(strings.TrimSuffix(threshold, "%"), "-")
+	case strings.HasPrefix(threshold, ">"):
+		alert.Direction = models.AlertDirectionAbove
+		threshold = strings.TrimPrefix(threshold, ">")
+	case strings.HasPrefix(threshold, "<"):
+		threshold = strings.TrimPrefix(threshold, "<")
+	}
+	value, err := strconv.ParseFloat(threshold, 64)
+	if err != nil || value <= 0 {
+		return errInvalidThreshold
+	}
+	if (alert.Kind == models.AlertKindDiscount || alert.Kind == models.AlertKindDrop) && value >= 100 {
+		return errInvalidThreshold
+	}
+	alert.Threshold = value
+	return nil
+}
+
+func parseWindow(arg string) (int, bool) {
+	token := strings.ToLower(arg)
+	for suffix, hours := range map[string]int{"h": 1, "ч": 1, "d": 24, "д": 24} {
+		if !strings.HasSuffix(token, suffix) {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSuffix(token, suffix))
+		if err != nil || value <= 0 || value*hours > 365*24 {
+			return 0, false
+		}
+		return value * hours, true
+	}
+	return 0, false
+}
+
+func alertTitle(alert models.Alert) string {
+	var rule string
+	switch {
+	case alert.Kind == models.AlertKindDiscount:
+		rule = fmt.Sprintf("скидка от %.0f%%", alert.Threshold)
+	case alert.Kind == models.AlertKindDrop:
+		rule = fmt.Sprintf("падение от %.1f%% за %s", alert.Threshold, models.FormatWindow(alert.WindowHours))
+	case alert.Kind == models.AlertKindLow:
+		rule = fmt.Sprintf("минимум за %s", models.FormatWindow(alert.WindowHours))
+	case alert.Kind == models.AlertKindChange && alert.Direction == models.AlertDirectionAbove:
+		rule = fmt.Sprintf("рост от %.2f", alert.Threshold)
+	case alert.Kind == models.AlertKindChange && alert.Direction == models.AlertDirectionBelow:
+		rule = fmt.Sprintf("снижение от %.2f", alert.Threshold)
+	case alert.Kind == models.AlertKindChange:
+		rule = fmt.Sprintf("изменение от %.2f", alert.Threshold)
+	case alert.Direction == models.AlertDirectionAbove:
+		rule = fmt.Sprintf("цена выше %.2f", alert.Threshold)
+	default:
+		rule = fmt.Sprintf("цена ниже %.2f", alert.Threshold)
+	}
+	product := []string{}
+	if alert.IPhoneId != nil {
+		product = append(product, *alert.IPhoneId)
+	}
+	if alert.Model != "" {
+		product = append(product, alert.Model)
+	}
+	if alert.ColorName != "" {
+		product = append(product, alert.ColorName)
+	}
+	if alert.Capacity != 0 {
+		product = append(product, fmt.Sprintf("%dGB", alert.Capacity))
+	}
+	title := rule + " · любой айфон"
+	if len(product) > 0 {
+		title = rule + " · " + strings.Join(product, " ")
+	}
+	switch alert.Repeat {
+	case models.AlertRepeatOnce:
+		title += " · один раз"
+	case models.AlertRepeatCooldown:
+		title += " · не чаще раза в " + models.FormatWindow(alert.CooldownHours)
+	}
+	if alert.Fired {
+		title += " · 🔕"
+	}
+	if alert.Paused {
+		title += " · ⏸ на паузе"
+	}
+	return title
+}
+
+func FiredAlertsMessage(alerts []models.FiredAlert) string {
+	msg := "🚨 сработали ваши алерты:\n"
+	for _, f := range alerts {
+		msg += fmt.Sprintf(" • %s: %s (алерт #%d)\n", f.IPhone.Name, f.Reason, f.Alert.Id)
+		if f.IPhone.BestOffer != nil {
+			msg += fmt.Sprintf("   🏷 купить за %.2f в [%s](%s)\n", f.IPhone.BestOffer.Price, f.IPhone.BestOffer.Source, f.IPhone.BestOffer.Url)
+		}
+	}
+	return msg + "\nуправлять алертами: /alerts"
+}
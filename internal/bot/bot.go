@@ -11,46 +11,66 @@ import (
 	"iFall/pkg/logger"
 	"strconv"
 	"strings"
-	"sync"
 
 	telebot "gopkg.in/telebot.v4"
 )
 
-type DataToSend struct {
-	Price  float64
-	ChatId int64
-}
-
 //go:generate mockgen -source=bot.go -destination=mocks/bot-mock.go
 type TelegramBot interface {
 	SetupTelegramBot()
-	SendIPhonesInfo(datas []DataToSend, iphones []models.IPhone) error
+	SendMessage(chatId int64, msg string) error
+	HandleUpdate(update telebot.Update)
 	Start()
 	Stop()
 }
 
 type telegramBot struct {
-	Bot             *telebot.Bot
-	Config          config.TelegramBotConfig
-	UserRepository  repositories.UserRepository
-	usersStatements sync.Map
-	Logger          *logger.Logger
+	Bot                         *telebot.Bot
+	Config                      config.TelegramBotConfig
+	UserRepository              repositories.UserRepository
+	IPhoneRepository            repositories.IPhoneRepository
+	PriceHistoryRepository      repositories.PriceHistoryRepository
+	OfferRepository             repositories.OfferRepository
+	StockSubscriptionRepository repositories.StockSubscriptionRepository
+	AlertRepository             repositories.AlertRepository
+	ChatStateRepository         repositories.ChatStateRepository
+	Logger                      *logger.Logger
 }
 
-func NewTelegramBot(cfg config.TelegramBotConfig, l *logger.Logger, ur repositories.UserRepository) TelegramBot {
+func NewTelegramBot(cfg config.TelegramBotConfig, l *logger.Logger, ur repositories.UserRepository, ir repositories.IPhoneRepository, phr repositories.PriceHistoryRepository, or repositories.OfferRepository, sr repositories.StockSubscriptionRepository, ar repositories.AlertRepository, csr repositories.ChatStateRepository) TelegramBot {
+	var poller telebot.Poller = &telebot.LongPoller{Timeout: cfg.Timeout}
+	if cfg.Mode == ModeWebhook {
+		if cfg.WebhookUrl == "" || cfg.WebhookSecret == "" {
+			panic(errors.New("telegram webhook mode requires webhook url and secret"))
+		}
+		poller = &webhookPoller{Config: cfg, Logger: l}
+	}
 	pref := telebot.Settings{
 		Token:  cfg.Token,
-		Poller: &telebot.LongPoller{Timeout: cfg.Timeout},
+		Poller: poller,
 	}
 	bot, err := telebot.NewBot(pref)
 	if err != nil {
 		panic(fmt.Errorf("failed to create new telegram bot: %w", err))
 	}
+	if cfg.Mode != ModeWebhook {
+		// telegram refuses long polling while a webhook from a previous run is still set
+		// a failed removal is not fatal, the poller keeps retrying and reports conflicts through OnError
+		if err := bot.RemoveWebhook(); err != nil {
+			l.AddOp(place+"NewTelegramBot").Error("failed to remove telegram webhook", logger.Err(err))
+		}
+	}
 	return &telegramBot{
-		Bot:            bot,
-		Config:         cfg,
-		UserRepository: ur,
-		Logger:         l,
+		Bot:                         bot,
+		Config:                      cfg,
+		UserRepository:              ur,
+		IPhoneRepository:            ir,
+		PriceHistoryRepository:      phr,
+		OfferRepository:             or,
+		StockSubscriptionRepository: sr,
+		AlertRepository:             ar,
+		ChatStateRepository:         csr,
+		Logger:                      l,
 	}
 }
 
@@ -58,148 +78,216 @@ const place = "telegramBot."
 
 func (tb *telegramBot) SetupTelegramBot() {
 	tb.choosePrice()
-	tb.storeChatId()
+	tb.chooseDiscount()
+	tb.manageAlerts()
+	tb.alertsMenu()
+	tb.registerUser()
+	tb.issueApiToken()
+	tb.notifyBackInStock()
+	tb.showPrices()
+	tb.showHistory()
+	tb.answerInline()
+	tb.handleText()
 }
 
-const (
-	storingChatId  = "s"
-	choosingPrice  = "c"
-	askingForPrice = "a"
-)
-
-func (tb *telegramBot) storeChatId() {
-	op := place + "storeChatId"
+func (tb *telegramBot) choosePrice() {
+	op := place + "choosePrice"
 	log := tb.Logger.AddOp(op)
-	yes := telebot.Btn{Unique: "store_chatid_yes", Text: "✅ да"}
-	no := telebot.Btn{Unique: "store_chatid_no", Text: "❌ нет"}
-
-	tb.Bot.Handle("/start", func(c telebot.Context) error {
+	yes := telebot.Btn{Unique: "choose_price_yes", Text: "✅ да2"}
+	no := telebot.Btn{Unique: "choose_price_no", Text: "❌ нет"}
+	tb.Bot.Handle("/setprice", func(c telebot.Context) error {
 		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if !ok || state == storingChatId {
-			tb.usersStatements.Store(c.Chat().ID, storingChatId)
-			markup := &telebot.ReplyMarkup{}
-			markup.Inline(markup.Row(yes, no))
-			return c.Send("хотите получать обновления цены айфончика 17??", markup)
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		exist, err := tb.UserRepository.CheckChatId(ctx, op, c.Sender().ID, chatId)
+		if errors.Is(err, errs.ErrNotFoundBase) {
+			return c.Send("сначала зарегистрируйтесь через /start")
 		}
-		return nil
-	})
-	tb.Bot.Handle(&yes, func(c telebot.Context) error {
-		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if ok && state.(string) == storingChatId {
-			defer tb.usersStatements.Delete(chatId)
-			username := c.Sender().Username
-			ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
-			defer cancel()
-			if err := tb.UserRepository.SetChatId(ctx, username, chatId); err != nil {
-				if errors.Is(err, errs.ErrAlreadyExistsBase) {
-					return c.Edit("вы уже получаете обновления")
-				}
-				log.Error("failed to set chat id", logger.Err(err))
-				return c.Edit("произошла ошибка, возможно вы не зарегестрированы((")
-			}
-			return c.Edit("ждите обновления))")
+		if err != nil {
+			log.Error("failed to check chat id", logger.Err(err))
+			return c.Send("произошла ошибка((")
 		}
-		return nil
+		if exist {
+			return c.Send("сначала на обновления подпишитесь")
+		}
+		ok, err := tb.fire(ctx, chatId, priceAsked)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		if !ok {
+			return nil
+		}
+		markup := &telebot.ReplyMarkup{}
+		markup.Inline(markup.Row(yes, no))
+		return c.Send("хотите установить цену айфончика при достижении которой жоско заспамлю??", markup)
 	})
 	tb.Bot.Handle(&no, func(c telebot.Context) error {
-		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if ok && state.(string) == storingChatId {
-			defer tb.usersStatements.Delete(chatId)
-			username := c.Sender().Username
-			ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
-			defer cancel()
-			if err := tb.UserRepository.DropChatId(ctx, username, chatId); err != nil {
-				if errors.Is(err, errs.ErrNotFoundBase) {
-					return c.Edit("вы не подписаны на обновления")
-				}
-				log.Error("failed to delete chat id", logger.Err(err))
-				return c.Edit("произошла ошибка((")
-			}
-			return c.Edit("обновлений не ждите((")
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		ok, err := tb.fire(ctx, c.Chat().ID, priceDeclined)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Edit("произошла ошибка((")
 		}
-		return nil
+		if !ok {
+			return nil
+		}
+		return c.Edit("нет так нет")
+	})
+	tb.Bot.Handle(&yes, func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		ok, err := tb.fire(ctx, c.Chat().ID, priceAccepted)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if !ok {
+			return nil
+		}
+		return c.Edit("напиши цену, например 2800.52")
 	})
 }
 
-func (tb *telegramBot) choosePrice() {
-	op := place + "choosePrice"
+func (tb *telegramBot) enterPrice(c telebot.Context) error {
+	op := place + "enterPrice"
 	log := tb.Logger.AddOp(op)
-	yes := telebot.Btn{Unique: "choose_price_yes", Text: "✅ да2"}
-	no := telebot.Btn{Unique: "choose_price_no", Text: "❌ нет"}
-	tb.Bot.Handle("/setprice", func(c telebot.Context) error {
-		chatId := c.Chat().ID
+	chatId := c.Chat().ID
+	ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+	defer cancel()
+	ok, err := tb.fire(ctx, chatId, priceEntered)
+	if err != nil {
+		log.Error("failed to change chat state", logger.Err(err))
+		return c.Send("произошла ошибка((")
+	}
+	if !ok {
+		return nil
+	}
+	strPrice := strings.TrimSpace(strings.ReplaceAll(c.Text(), ",", "."))
+	if strings.HasPrefix(strPrice, "-") {
+		return c.Send("❌ цена не может быть отрицательной")
+	}
+	price, err := strconv.ParseFloat(strPrice, 32)
+	if err != nil {
+		return c.Send("❌ неправильный формат цены!!")
+	}
+	alert := &models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: price}
+	if err := tb.AlertRepository.Create(ctx, alert, models.Contacts{ChatId: &chatId}); err != nil {
+		log.Error("failed to create price alert", logger.Err(err))
+		return c.Send("произошла ошибка((")
+	}
+	return c.Send(fmt.Sprintf("✅ цена установлена: %.2f\nуточнить модель, цвет и память можно через /alert", price))
+}
+
+// handleText routes free text to the step the chat is currently at.
+func (tb *telegramBot) handleText() {
+	op := place + "handleText"
+	log := tb.Logger.AddOp(op)
+	tb.Bot.Handle(telebot.OnText, func(c telebot.Context) error {
 		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
 		defer cancel()
-		exist, err := tb.UserRepository.CheckChatId(ctx, op, c.Sender().Username, chatId)
+		state, data, err := tb.chatState(ctx, c.Chat().ID)
 		if err != nil {
-			log.Error("failed to check chat id", logger.Err(err))
+			log.Error("failed to receive chat state", logger.Err(err))
 			return c.Send("произошла ошибка((")
 		}
-		if !exist {
-			_, ok := tb.usersStatements.Load(chatId)
-			if !ok {
-				tb.usersStatements.Store(c.Chat().ID, askingForPrice)
-				markup := &telebot.ReplyMarkup{}
-				markup.Inline(markup.Row(yes, no))
-				return c.Send("хотите установить цену айфончика при достижении которой жоско заспамлю??", markup)
-			}
-		} else {
-			return c.Send("сначала на обновления подпишитесь")
+		switch state {
+		case choosingPrice:
+			return tb.enterPrice(c)
+		case askingName:
+			return tb.enterName(c)
+		case askingEmail:
+			return tb.enterEmail(c, data)
 		}
 		return nil
 	})
-	tb.Bot.Handle(&no, func(c telebot.Context) error {
+}
+
+func (tb *telegramBot) chooseDiscount() {
+	op := place + "chooseDiscount"
+	log := tb.Logger.AddOp(op)
+	tb.Bot.Handle("/setdiscount", func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) != 1 {
+			return c.Send("напиши скидку в процентах, например /setdiscount 10")
+		}
+		strDiscount := strings.TrimSuffix(strings.TrimSpace(strings.ReplaceAll(args[0], ",", ".")), "%")
+		discount, err := strconv.ParseFloat(strDiscount, 64)
+		if err != nil {
+			return c.Send("❌ неправильный формат скидки!!")
+		}
+		if discount <= 0 || discount >= 100 {
+			return c.Send("❌ скидка должна быть от 0 до 100")
+		}
 		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if ok && state.(string) == askingForPrice {
-			tb.usersStatements.Delete(chatId)
-			ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
-			defer cancel()
-			if err := tb.UserRepository.DropDesiredPrice(ctx, chatId); err != nil {
-				log.Error("failed to drop desired price", logger.Err(err))
-				return c.Edit("произошла ошибка((")
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		alert := &models.Alert{Kind: models.AlertKindDiscount, Direction: models.AlertDirectionAbove, Threshold: discount}
+		if err := tb.AlertRepository.Create(ctx, alert, models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Send("сначала на обновления подпишитесь")
 			}
-			return c.Edit("нет так нет")
+			log.Error("failed to create discount alert", logger.Err(err))
+			return c.Send("произошла ошибка((")
 		}
-		return nil
+		return c.Send(fmt.Sprintf("✅ скидка установлена: %.0f%%", discount))
 	})
-	tb.Bot.Handle(&yes, func(c telebot.Context) error {
-		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if ok && state.(string) == askingForPrice {
-			tb.usersStatements.Store(chatId, choosingPrice)
-			return c.Edit("напиши цену, например 2800.52")
+}
+
+func (tb *telegramBot) notifyBackInStock() {
+	op := place + "notifyBackInStock"
+	log := tb.Logger.AddOp(op)
+	notify := telebot.Btn{Unique: "notify_back"}
+	tb.Bot.Handle("/notify", func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		available := false
+		iphones, _, err := tb.IPhoneRepository.Fetch(ctx, models.IPhoneFilter{Available: &available, Limit: 50})
+		if err != nil {
+			log.Error("failed to fetch unavailable iphones", logger.Err(err))
+			return c.Send("произошла ошибка((")
 		}
-		return nil
+		if len(iphones) == 0 {
+			return c.Send("все айфончики в наличии))")
+		}
+		markup := &telebot.ReplyMarkup{}
+		rows := []telebot.Row{}
+		for _, iphone := range iphones {
+			btn := markup.Data(fmt.Sprintf("%s %s", availabilityMark(iphone.Availability), iphone.Name), notify.Unique, iphone.Id)
+			rows = append(rows, markup.Row(btn))
+		}
+		markup.Inline(rows...)
+		return c.Send("каких айфончиков ждете? сообщу когда появятся", markup)
 	})
-	tb.Bot.Handle(telebot.OnText, func(c telebot.Context) error {
+	tb.Bot.Handle(&notify, func(c telebot.Context) error {
 		chatId := c.Chat().ID
-		state, ok := tb.usersStatements.Load(chatId)
-		if ok && state.(string) == choosingPrice {
-			defer tb.usersStatements.Delete(chatId)
-			strPrice := strings.TrimSpace(strings.ReplaceAll(c.Text(), ",", "."))
-			if strings.HasPrefix(strPrice, "-") {
-				return c.Send("❌ цена не может быть отрицательной")
-			}
-			price, err := strconv.ParseFloat(strPrice, 32)
-			if err != nil {
-				return c.Send("❌ неправильный формат цены!!")
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		if err := tb.StockSubscriptionRepository.Create(ctx, c.Data(), models.Contacts{ChatId: &chatId}); err != nil {
+			if errors.Is(err, errs.ErrAlreadyExistsBase) {
+				return c.Edit("вы уже ждете этот айфончик")
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
-			defer cancel()
-			if err := tb.UserRepository.SetDesiredPrice(ctx, chatId, price); err != nil {
-				log.Error("failed to set desired price", logger.Err(err))
-				return c.Send("произошла ошибка((")
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Edit("сначала на обновления подпишитесь")
 			}
-			return c.Send(fmt.Sprintf("✅ цена установлена: %.2f", price))
+			log.Error("failed to create stock subscription", logger.Err(err))
+			return c.Edit("произошла ошибка((")
 		}
-		return nil
+		return c.Edit("✅ сообщу когда появится в наличии")
 	})
 }
 
+func availabilityMark(availability string) string {
+	switch availability {
+	case models.AvailabilityOutOfStock:
+		return "❌"
+	case models.AvailabilityPreorder:
+		return "⏳"
+	}
+	return "✅"
+}
+
 const (
 	grafUp   = "📈"
 	grafDown = "📉"
@@ -213,29 +301,9 @@ const (
 	zero     = ""
 )
 
-func (tb *telegramBot) SendIPhonesInfo(datas []DataToSend, iphones []models.IPhone) error {
-	op := place + "SendIphoneInfo"
+func IPhonesInfoMessage(iphones []models.IPhone) string {
 	msgArr := []string{}
-	spam := sync.Map{}
-	var wg sync.WaitGroup
-	pricesChan := make(chan float64, len(iphones))
-
-	for _, data := range datas {
-		wg.Add(1)
-		go func(d DataToSend) {
-			defer wg.Done()
-			for price := range pricesChan {
-				if d.Price >= price && d.Price != 0 {
-					spam.Store(d.ChatId, struct{}{})
-				}
-			}
-		}(data)
-	}
-
 	for _, iphone := range iphones {
-
-		pricesChan <- iphone.Price
-
 		graf := grafDef
 		color := white
 		sign := zero
@@ -258,36 +326,41 @@ func (tb *telegramBot) SendIPhonesInfo(datas []DataToSend, iphones []models.IPho
 		} else if iphone.Change < 0 {
 			graf = grafDown
 		}
-		msgArr = append(msgArr, fmt.Sprintf("%s %s:\n 💰 цена: %.2f | %s разница: %s%.2f\n", iphone.Name, color, iphone.Price, graf, sign, iphone.Change))
-	}
-	close(pricesChan)
-	wg.Wait()
-	msg := strings.Join(msgArr, "\n")
-	errChan := make(chan error, len(datas))
-	wg = sync.WaitGroup{}
-	for _, data := range datas {
-		wg.Add(1)
-		go func(data DataToSend) {
-			defer wg.Done()
-			n := 1
-			message := msg
-			if _, ok := spam.Load(data.ChatId); ok {
-				n = 15
-				message += fmt.Sprintf("\nкакой-то айфон стоит сток скок вы хотели❗❗❗\nа именно %.2f 🥶🥶🥶", data.Price)
-			}
-			for range n {
-				if _, err := tb.Bot.Send(&telebot.Chat{ID: data.ChatId}, message, telebot.ModeMarkdown); err != nil {
-					errChan <- err
-				}
+		msg := fmt.Sprintf("%s %s:\n 💰 цена: %.2f | %s разница: %s%.2f\n", iphone.Name, color, iphone.Price, graf, sign, iphone.Change)
+		if iphone.Discount > 0 {
+			msg += fmt.Sprintf(" 🔻 -%.0f%% от %.2f\n", iphone.Discount, iphone.OldPrice)
+		}
+		if iphone.Installment > 0 {
+			msg += fmt.Sprintf(" 💳 рассрочка от %.2f/мес\n", iphone.Installment)
+		}
+		switch iphone.Availability {
+		case models.AvailabilityOutOfStock:
+			msg += fmt.Sprintf(" %s нет в наличии\n", availabilityMark(iphone.Availability))
+		case models.AvailabilityPreorder:
+			msg += fmt.Sprintf(" %s предзаказ\n", availabilityMark(iphone.Availability))
+		}
+		if iphone.BestOffer != nil {
+			msg += fmt.Sprintf(" 🏷 лучшая цена: %.2f в [%s](%s)\n", iphone.BestOffer.Price, iphone.BestOffer.Source, iphone.BestOffer.Url)
+			if iphone.Spread > 0 {
+				msg += fmt.Sprintf(" ↔️ разброс по магазинам: %.2f\n", iphone.Spread)
 			}
+		}
+		msgArr = append(msgArr, msg)
+	}
+	return strings.Join(msgArr, "\n")
+}
 
-		}(data)
+func BackInStockMessage(iphone models.IPhone) string {
+	msg := fmt.Sprintf("🔥 %s снова в наличии!\n 💰 цена: %.2f\n", iphone.Name, iphone.Price)
+	if iphone.BestOffer != nil {
+		msg += fmt.Sprintf(" 🏷 лучшая цена: %.2f в [%s](%s)\n", iphone.BestOffer.Price, iphone.BestOffer.Source, iphone.BestOffer.Url)
 	}
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
-	for err := range errChan {
+	return msg
+}
+
+func (tb *telegramBot) SendMessage(chatId int64, msg string) error {
+	op := place + "SendMessage"
+	if _, err := tb.Bot.Send(&telebot.Chat{ID: chatId}, msg, telebot.ModeMarkdown); err != nil {
 		return errs.NewAppError(op, err)
 	}
 	return nil
@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	chartWidth  = 800
+	chartHeight = 400
+	chartLeft   = 70
+	chartRight  = 30
+	chartTop    = 30
+	chartBottom = 40
+	// chartGap merges records of one scrape run coming from different sources.
+	chartGap = 10 * time.Minute
+)
+
+var (
+	chartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	chartGrid       = color.RGBA{0xe5, 0xe5, 0xe5, 0xff}
+	chartAxis       = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	chartText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	chartLine       = color.RGBA{0x1e, 0x6f, 0xd9, 0xff}
+	chartMin        = color.RGBA{0x2e, 0xa0, 0x43, 0xff}
+	chartMax        = color.RGBA{0xd9, 0x3a, 0x2e, 0xff}
+)
+
+var errNotEnoughPoints = errors.New("not enough points")
+
+type chartPoint struct {
+	At    time.Time
+	Price float64
+}
+
+// chartPoints keeps the best available price of every scrape run.
+func chartPoints(records []models.PriceRecord) []chartPoint {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	points := []chartPoint{}
+	for _, r := range records {
+		if !r.Available || r.Price <= 0 {
+			continue
+		}
+		last := len(points) - 1
+		if last >= 0 && r.CreatedAt.Sub(points[last].At) < chartGap {
+			points[last].Price = math.Min(points[last].Price, r.Price)
+			continue
+		}
+		points = append(points, chartPoint{At: r.CreatedAt, Price: r.Price})
+	}
+	return points
+}
+
+// extremes returns indexes of the first minimum and the first maximum.
+func extremes(points []chartPoint) (int, int) {
+	lo, hi := 0, 0
+	for i, p := range points {
+		if p.Price < points[lo].Price {
+			lo = i
+		}
+		if p.Price > points[hi].Price {
+			hi = i
+		}
+	}
+	return lo, hi
+}
+
+func renderPriceChart(points []chartPoint) ([]byte, error) {
+	if len(points) < 2 {
+		return nil, errNotEnoughPoints
+	}
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	lo, hi := extremes(points)
+	minPrice, maxPrice := points[lo].Price, points[hi].Price
+	pad := (maxPrice - minPrice) * 0.1
+	if pad == 0 {
+		pad = math.Max(minPrice*0.01, 1)
+	}
+	minPrice, maxPrice = minPrice-pad, maxPrice+pad
+	from, to := points[0].At, points[len(points)-1].At
+	plotW, plotH := chartWidth-chartLeft-chartRight, chartHeight-chartTop-chartBottom
+	x := func(t time.Time) int {
+		return chartLeft + int(float64(plotW)*t.Sub(from).Seconds()/to.Sub(from).Seconds())
+	}
+	y := func(price float64) int {
+		return chartTop + plotH - int(float64(plotH)*(price-minPrice)/(maxPrice-minPrice))
+	}
+
+	for i := 0; i <= 4; i++ {
+		price := minPrice + (maxPrice-minPrice)*float64(i)/4
+		py := y(price)
+		drawLine(img, chartLeft, py, chartWidth-chartRight, py, chartGrid, 1)
+		drawText(img, 5, py+4, fmt.Sprintf("%.2f", price), chartText)
+	}
+	drawLine(img, chartLeft, chartTop, chartLeft, chartTop+plotH, chartAxis, 1)
+	drawLine(img, chartLeft, chartTop+plotH, chartWidth-chartRight, chartTop+plotH, chartAxis, 1)
+	for i := 0; i <= 4; i++ {
+		t := from.Add(to.Sub(from) * time.Duration(i) / 4)
+		label := t.Local().Format("02.01")
+		drawText(img, x(t)-len(label)*7/2, chartHeight-chartBottom+20, label, chartText)
+	}
+
+	for i := 1; i < len(points); i++ {
+		drawLine(img, x(points[i-1].At), y(points[i-1].Price), x(points[i].At), y(points[i].Price), chartLine, 2)
+	}
+	current := len(points) - 1
+	marks := []struct {
+		index int
+		title string
+		color color.RGBA
+	}{
+		{lo, "min", chartMin},
+		{hi, "max", chartMax},
+		{current, "now", chartLine},
+	}
+	for _, m := range marks {
+		p := points[m.index]
+		px, py := x(p.At), y(p.Price)
+		drawDot(img, px, py, 5, m.color)
+		label := fmt.Sprintf("%s %.2f", m.title, p.Price)
+		lx := min(max(px-len(label)*7/2, chartLeft), chartWidth-chartRight-len(label)*7)
+		ly := py - 10
+		if m.index == lo {
+			ly = py + 20
+		}
+		drawText(img, lx, ly, label, m.color)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA, width int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		for i := 0; i < width; i++ {
+			for j := 0; j < width; j++ {
+				img.SetRGBA(x0+i, y0+j, c)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func drawDot(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(cx+dx, cy+dy, c)
+			}
+		}
+	}
+}
+
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
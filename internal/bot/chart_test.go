@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"bytes"
+	"iFall/internal/domain/models"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartPoints(t *testing.T) {
+	now := time.Now()
+	records := []models.PriceRecord{
+		{Price: 2900, Available: true, CreatedAt: now.Add(-48 * time.Hour)},
+		{Price: 2850, Available: true, CreatedAt: now.Add(-48*time.Hour + time.Minute)},
+		{Price: 2700, Available: false, CreatedAt: now.Add(-24 * time.Hour)},
+		{Price: 2800, Available: true, CreatedAt: now},
+	}
+	points := chartPoints(records)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 2850.0, points[0].Price)
+	assert.Equal(t, 2800.0, points[1].Price)
+}
+
+func TestRenderPriceChart(t *testing.T) {
+	now := time.Now()
+	t.Run("success", func(t *testing.T) {
+		points := []chartPoint{
+			{At: now.Add(-72 * time.Hour), Price: 2900},
+			{At: now.Add(-48 * time.Hour), Price: 3100},
+			{At: now.Add(-24 * time.Hour), Price: 2750},
+			{At: now, Price: 2800},
+		}
+		chart, err := renderPriceChart(points)
+		assert.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(chart))
+		assert.NoError(t, err)
+		assert.Equal(t, chartWidth, img.Bounds().Dx())
+		assert.Equal(t, chartHeight, img.Bounds().Dy())
+	})
+
+	t.Run("success with flat price", func(t *testing.T) {
+		_, err := renderPriceChart([]chartPoint{{At: now.Add(-time.Hour), Price: 2800}, {At: now, Price: 2800}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed with single point", func(t *testing.T) {
+		_, err := renderPriceChart([]chartPoint{{At: now, Price: 2800}})
+		assert.ErrorIs(t, err, errNotEnoughPoints)
+	})
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+const historyUsage = `напиши модель и, если нужно, цвет, память и период:
+/history 17 green 256 — за последние 30 дней
+/history 17 pro 7d — за неделю`
+
+const defaultHistoryWindow = 30 * 24
+
+func (tb *telegramBot) showHistory() {
+	op := place + "showHistory"
+	log := tb.Logger.AddOp(op)
+	pick := telebot.Btn{Unique: "history_pick"}
+	send := func(c telebot.Context, iphone models.IPhone, hours int) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		now := time.Now()
+		records, err := tb.PriceHistoryRepository.FetchByRange(ctx, iphone.Id, now.Add(-time.Duration(hours)*time.Hour), now)
+		if err != nil {
+			log.Error("failed to fetch price history", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		points := chartPoints(records)
+		chart, err := renderPriceChart(points)
+		if err != nil {
+			if errors.Is(err, errNotEnoughPoints) {
+				return c.Send(fmt.Sprintf("по %s пока мало данных за %s, цены проверяются дважды в день", iphone.Name, models.FormatWindow(hours)))
+			}
+			log.Error("failed to render price chart", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		photo := &telebot.Photo{File: telebot.FromReader(bytes.NewReader(chart)), Caption: historyCaption(iphone, points, hours)}
+		return c.Send(photo)
+	}
+	tb.Bot.Handle("/history", func(c telebot.Context) error {
+		args := c.Args()
+		hours := defaultHistoryWindow
+		rest := []string{}
+		for _, arg := range args {
+			if h, ok := parseWindow(arg); ok {
+				hours = h
+				continue
+			}
+			rest = append(rest, arg)
+		}
+		if len(rest) == 0 {
+			return c.Send(historyUsage)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		filter, err := parsePricesFilter(rest, iphones)
+		if err != nil {
+			return c.Send("❌ не знаю такой модели\n\n" + historyUsage)
+		}
+		matched := []models.IPhone{}
+		for _, iphone := range iphones {
+			if filter.matches(iphone) {
+				matched = append(matched, iphone)
+			}
+		}
+		switch len(matched) {
+		case 0:
+			return c.Send("таких айфончиков нет\n\n" + historyUsage)
+		case 1:
+			return send(c, matched[0], hours)
+		}
+		markup := &telebot.ReplyMarkup{}
+		rows := []telebot.Row{}
+		for _, iphone := range matched {
+			btn := markup.Data(iphone.Name, pick.Unique, iphone.Id, strconv.Itoa(hours))
+			rows = append(rows, markup.Row(btn))
+		}
+		markup.Inline(rows...)
+		return c.Send("какой именно айфончик?", markup)
+	})
+	tb.Bot.Handle(&pick, func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) != 2 {
+			return c.Edit("произошла ошибка((")
+		}
+		hours, err := strconv.Atoi(args[1])
+		if err != nil {
+			return c.Edit("произошла ошибка((")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphone, err := tb.IPhoneRepository.Get(ctx, args[0])
+		if err != nil {
+			log.Error("failed to receive iphone", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if err := c.Delete(); err != nil {
+			log.Error("failed to delete picker", logger.Err(err))
+		}
+		return send(c, *iphone, hours)
+	})
+}
+
+func historyCaption(iphone models.IPhone, points []chartPoint, hours int) string {
+	lo, hi := extremes(points)
+	first, current := points[0], points[len(points)-1]
+	lines := []string{
+		fmt.Sprintf("📈 %s за %s", iphone.Name, models.FormatWindow(hours)),
+		fmt.Sprintf(" 💰 сейчас: %.2f", current.Price),
+		fmt.Sprintf(" 🟢 минимум: %.2f (%s)", points[lo].Price, points[lo].At.Local().Format("02.01")),
+		fmt.Sprintf(" 🔴 максимум: %.2f (%s)", points[hi].Price, points[hi].At.Local().Format("02.01")),
+	}
+	change := current.Price - first.Price
+	lines = append(lines, fmt.Sprintf(" ↕️ за период: %+.2f (%+.1f%%)", change, change/first.Price*100))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/pkg/logger"
+	"strings"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+const (
+	// inlineLimit is the most results telegram accepts in one answer.
+	inlineLimit = 50
+	// inlineCacheTime keeps shared prices close to the latest scrape, in seconds.
+	inlineCacheTime = 60
+)
+
+// answerInline serves "@bot green" typed in any chat, inline mode has to be enabled with BotFather.
+func (tb *telegramBot) answerInline() {
+	op := place + "answerInline"
+	log := tb.Logger.AddOp(op)
+	tb.Bot.Handle(telebot.OnQuery, func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return err
+		}
+		offers, err := tb.OfferRepository.FetchBest(ctx)
+		if err != nil {
+			log.Error("failed to fetch offers", logger.Err(err))
+		}
+		results := telebot.Results{}
+		for _, iphone := range inlineMatches(c.Query().Text, iphones) {
+			if offer, ok := offers[iphone.Id]; ok {
+				iphone.BestOffer = &offer
+			}
+			results = append(results, inlineResult(iphone))
+		}
+		return c.Answer(&telebot.QueryResponse{
+			Results:   results,
+			CacheTime: inlineCacheTime,
+		})
+	})
+}
+
+// inlineMatches reuses the /prices filter, so an unknown model yields no results.
+func inlineMatches(query string, iphones []models.IPhone) []models.IPhone {
+	filter, err := parsePricesFilter(strings.Fields(query), iphones)
+	if err != nil {
+		return nil
+	}
+	matched := []models.IPhone{}
+	for _, iphone := range iphones {
+		if filter.matches(iphone) && iphone.Price > 0 {
+			matched = append(matched, iphone)
+		}
+		if len(matched) == inlineLimit {
+			break
+		}
+	}
+	return matched
+}
+
+func inlineResult(iphone models.IPhone) *telebot.ArticleResult {
+	graf := grafDef
+	if iphone.Change > 0 {
+		graf = grafUp
+	} else if iphone.Change < 0 {
+		graf = grafDown
+	}
+	description := fmt.Sprintf("💰 %.2f | %s %+.2f", iphone.Price, graf, iphone.Change)
+	result := &telebot.ArticleResult{
+		Title:       iphone.Name,
+		Text:        IPhonesInfoMessage([]models.IPhone{iphone}),
+		Description: description,
+	}
+	if iphone.BestOffer != nil {
+		result.URL = iphone.BestOffer.Url
+		result.HideURL = true
+		result.Description += fmt.Sprintf(" · от %.2f в %s", iphone.BestOffer.Price, iphone.BestOffer.Source)
+	}
+	result.SetResultID(iphone.Id)
+	result.ParseMode = telebot.ModeMarkdown
+	return result
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"iFall/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	telebot "gopkg.in/telebot.v4"
+)
+
+func TestInlineMatches(t *testing.T) {
+	iphones := []models.IPhone{
+		{Id: "iphone-green-id", Name: "iPhone 17 256GB Green", Model: "iPhone 17", ColorName: "Green", Capacity: 256, Price: 2750},
+		{Id: "iphone-black-id", Name: "iPhone 17 512GB Black", Model: "iPhone 17", ColorName: "Black", Capacity: 512, Price: 3600},
+		{Id: "iphone-pro-green-id", Name: "iPhone 17 Pro 256GB Green", Model: "iPhone 17 Pro", ColorName: "Green", Capacity: 256, Price: 0},
+	}
+	tests := []struct {
+		testName    string
+		query       string
+		expectedIds []string
+	}{
+		{
+			testName:    "empty query shows everything priced",
+			query:       "",
+			expectedIds: []string{"iphone-green-id", "iphone-black-id"},
+		},
+		{
+			testName:    "color",
+			query:       "green",
+			expectedIds: []string{"iphone-green-id"},
+		},
+		{
+			testName:    "model and capacity",
+			query:       "17 512",
+			expectedIds: []string{"iphone-black-id"},
+		},
+		{
+			testName:    "unknown model",
+			query:       "16e",
+			expectedIds: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			ids := []string{}
+			for _, iphone := range inlineMatches(tt.query, iphones) {
+				ids = append(ids, iphone.Id)
+			}
+			assert.Equal(t, tt.expectedIds, ids)
+		})
+	}
+}
+
+func TestInlineResult(t *testing.T) {
+	iphone := models.IPhone{Id: "iphone-green-id", Name: "iPhone 17 256GB Green", Price: 2750, Change: -200}
+	result := inlineResult(iphone)
+	assert.Equal(t, "iphone-green-id", result.ResultID())
+	assert.Equal(t, "💰 2750.00 | 📉 -200.00", result.Description)
+	assert.Empty(t, result.URL)
+
+	iphone.BestOffer = &models.Offer{Source: "newton.by", Url: "https://newton.by/iphone-green-id", Price: 2700}
+	result = inlineResult(iphone)
+	assert.Equal(t, "https://newton.by/iphone-green-id", result.URL)
+	assert.Equal(t, "💰 2750.00 | 📉 -200.00 · от 2700.00 в newton.by", result.Description)
+	assert.Contains(t, result.Text, "[newton.by](https://newton.by/iphone-green-id)")
+	assert.Equal(t, telebot.ModeMarkdown, result.ParseMode)
+}
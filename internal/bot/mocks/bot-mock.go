@@ -5,11 +5,10 @@
 package mock_bot
 
 import (
-	bot "iFall/internal/bot"
-	models "iFall/internal/domain/models"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	telebot "gopkg.in/telebot.v4"
 )
 
 // MockTelegramBot is a mock of TelegramBot interface.
@@ -35,18 +34,30 @@ func (m *MockTelegramBot) EXPECT() *MockTelegramBotMockRecorder {
 	return m.recorder
 }
 
-// SendIPhonesInfo mocks base method.
-func (m *MockTelegramBot) SendIPhonesInfo(datas []bot.DataToSend, iphones []models.IPhone) error {
+// HandleUpdate mocks base method.
+func (m *MockTelegramBot) HandleUpdate(update telebot.Update) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendIPhonesInfo", datas, iphones)
+	m.ctrl.Call(m, "HandleUpdate", update)
+}
+
+// HandleUpdate indicates an expected call of HandleUpdate.
+func (mr *MockTelegramBotMockRecorder) HandleUpdate(update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUpdate", reflect.TypeOf((*MockTelegramBot)(nil).HandleUpdate), update)
+}
+
+// SendMessage mocks base method.
+func (m *MockTelegramBot) SendMessage(chatId int64, msg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", chatId, msg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SendIPhonesInfo indicates an expected call of SendIPhonesInfo.
-func (mr *MockTelegramBotMockRecorder) SendIPhonesInfo(datas, iphones interface{}) *gomock.Call {
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockTelegramBotMockRecorder) SendMessage(chatId, msg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendIPhonesInfo", reflect.TypeOf((*MockTelegramBot)(nil).SendIPhonesInfo), datas, iphones)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockTelegramBot)(nil).SendMessage), chatId, msg)
 }
 
 // SetupTelegramBot mocks base method.
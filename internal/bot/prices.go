@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/pkg/logger"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+const pricesUsage = `можно уточнить модель, цвет, память и esim:
+/prices — все айфончики
+/prices green — только зеленые
+/prices esim — только с esim, sim — с физической симкой
+/prices 17 pro 256 — конкретная модель и память`
+
+// pricesLimit keeps the table within a single telegram message.
+const pricesLimit = 15
+
+// callbackLimit is the most bytes telegram keeps in callback_data, telebot adds "\f<unique>|" in front.
+const callbackLimit = 64
+
+type pricesFilter struct {
+	product
+	Esim *bool
+}
+
+func (tb *telegramBot) showPrices() {
+	op := place + "showPrices"
+	log := tb.Logger.AddOp(op)
+	drill := telebot.Btn{Unique: "prices_drill"}
+	send := func(c telebot.Context, args []string, edit bool) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		iphones, err := tb.IPhoneRepository.FetchActive(ctx)
+		if err != nil {
+			log.Error("failed to fetch iphones", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		filter, err := parsePricesFilter(args, iphones)
+		if err != nil {
+			if errors.Is(err, errUnknownModel) {
+				return c.Send("❌ не знаю такой модели\n\n" + pricesUsage)
+			}
+			return c.Send("произошла ошибка((")
+		}
+		matched := []models.IPhone{}
+		for _, iphone := range iphones {
+			if filter.matches(iphone) {
+				matched = append(matched, iphone)
+			}
+		}
+		if len(matched) == 0 {
+			return c.Send("таких айфончиков нет\n\n" + pricesUsage)
+		}
+		msg := IPhonesInfoMessage(matched[:min(len(matched), pricesLimit)])
+		if len(matched) > pricesLimit {
+			msg += fmt.Sprintf("\n...и еще %d, уточни фильтр\n\n%s", len(matched)-pricesLimit, pricesUsage)
+		}
+		markup := &telebot.ReplyMarkup{}
+		rows := []telebot.Row{}
+		for _, next := range filter.drillDown(matched) {
+			data := strings.Join(next.args(), " ")
+			if !fitsCallback(drill.Unique, data) {
+				continue
+			}
+			rows = append(rows, markup.Row(markup.Data(next.title(), drill.Unique, data)))
+		}
+		markup.Inline(rows...)
+		if edit {
+			return c.Edit(msg, markup, telebot.ModeMarkdown)
+		}
+		return c.Send(msg, markup, telebot.ModeMarkdown)
+	}
+	tb.Bot.Handle("/prices", func(c telebot.Context) error {
+		return send(c, c.Args(), false)
+	})
+	tb.Bot.Handle(&drill, func(c telebot.Context) error {
+		return send(c, strings.Fields(c.Data()), true)
+	})
+}
+
+func parsePricesFilter(args []string, iphones []models.IPhone) (pricesFilter, error) {
+	filter := pricesFilter{}
+	rest := []string{}
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "esim":
+			esim := true
+			filter.Esim = &esim
+		case "sim":
+			esim := false
+			filter.Esim = &esim
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	p, err := parseProduct(rest, iphones)
+	if err != nil {
+		return filter, err
+	}
+	filter.product = p
+	return filter, nil
+}
+
+func (f pricesFilter) matches(iphone models.IPhone) bool {
+	if f.Model != "" && !strings.EqualFold(f.Model, iphone.Model) {
+		return false
+	}
+	if f.ColorName != "" && !strings.EqualFold(f.ColorName, iphone.ColorName) {
+		return false
+	}
+	if f.Capacity != 0 && f.Capacity != iphone.Capacity {
+		return false
+	}
+	if f.Esim != nil && *f.Esim != iphone.Esim {
+		return false
+	}
+	return true
+}
+
+// drillDown offers one narrower filter per model, or per color once the model is fixed.
+func (f pricesFilter) drillDown(iphones []models.IPhone) []pricesFilter {
+	byColor := f.Model != "" || countModels(iphones) == 1
+	next := []pricesFilter{}
+	seen := map[string]bool{}
+	for _, iphone := range iphones {
+		n := f
+		n.Model = iphone.Model
+		key := iphone.Model
+		if byColor {
+			n.ColorName = iphone.ColorName
+			key = iphone.ColorName
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		next = append(next, n)
+	}
+	if len(next) < 2 {
+		return nil
+	}
+	return next
+}
+
+func countModels(iphones []models.IPhone) int {
+	seen := map[string]bool{}
+	for _, iphone := range iphones {
+		seen[iphone.Model] = true
+	}
+	return len(seen)
+}
+
+// fitsCallback also refuses values with "|", telebot splits the callback data on it.
+func fitsCallback(unique string, data ...string) bool {
+	for _, value := range data {
+		if strings.Contains(value, "|") {
+			return false
+		}
+	}
+	return len("\f"+unique+"|"+strings.Join(data, "|")) <= callbackLimit
+}
+
+// args is the shortest form parseProduct reads back, the "iPhone " prefix is implied.
+func (f pricesFilter) args() []string {
+	args := []string{}
+	if f.Model != "" {
+		args = append(args, strings.TrimPrefix(f.Model, "iPhone "))
+	}
+	if f.ColorName != "" {
+		args = append(args, f.ColorName)
+	}
+	if f.Capacity != 0 {
+		args = append(args, strconv.Itoa(f.Capacity))
+	}
+	if f.Esim != nil && *f.Esim {
+		args = append(args, "esim")
+	} else if f.Esim != nil {
+		args = append(args, "sim")
+	}
+	return args
+}
+
+func (f pricesFilter) title() string {
+	if f.ColorName != "" {
+		return fmt.Sprintf("🔍 %s %s", f.Model, f.ColorName)
+	}
+	return "🔍 " + f.Model
+}
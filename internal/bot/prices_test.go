@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"iFall/internal/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pricesIPhones = []models.IPhone{
+	{Id: "17-black-256", Model: "iPhone 17", ColorName: "Black", Capacity: 256},
+	{Id: "17-green-512-esim", Model: "iPhone 17", ColorName: "Green", Capacity: 512, Esim: true},
+	{Id: "17-pro-orange-256", Model: "iPhone 17 Pro", ColorName: "Cosmic Orange", Capacity: 256},
+	{Id: "17-pro-max-orange-1024-esim", Model: "iPhone 17 Pro Max", ColorName: "Cosmic Orange", Capacity: 1024, Esim: true},
+	{Id: "17-pro-max-blue-256", Model: "iPhone 17 Pro Max", ColorName: "Deep Blue", Capacity: 256},
+}
+
+func TestParsePricesFilter(t *testing.T) {
+	esim, sim := true, false
+	tests := []struct {
+		testName       string
+		args           []string
+		expectedFilter pricesFilter
+		expectedError  error
+	}{
+		{
+			testName:       "success without args",
+			args:           nil,
+			expectedFilter: pricesFilter{},
+		},
+		{
+			testName:       "success esim",
+			args:           []string{"ESIM"},
+			expectedFilter: pricesFilter{Esim: &esim},
+		},
+		{
+			testName:       "success sim with model and capacity",
+			args:           []string{"17", "pro", "max", "256gb", "sim"},
+			expectedFilter: pricesFilter{product: product{Model: "iPhone 17 Pro Max", Capacity: 256}, Esim: &sim},
+		},
+		{
+			testName:       "success multi-word color",
+			args:           []string{"cosmic", "orange", "esim"},
+			expectedFilter: pricesFilter{product: product{ColorName: "Cosmic Orange"}, Esim: &esim},
+		},
+		{
+			testName:      "failed with unknown model",
+			args:          []string{"18", "air"},
+			expectedError: errUnknownModel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			filter, err := parsePricesFilter(tt.args, pricesIPhones)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFilter, filter)
+		})
+	}
+}
+
+func TestPricesFilter_DrillDown(t *testing.T) {
+	esim := true
+	tests := []struct {
+		testName       string
+		filter         pricesFilter
+		expectedTitles []string
+	}{
+		{
+			testName:       "by model",
+			filter:         pricesFilter{},
+			expectedTitles: []string{"🔍 iPhone 17", "🔍 iPhone 17 Pro", "🔍 iPhone 17 Pro Max"},
+		},
+		{
+			testName:       "by color once the model is fixed",
+			filter:         pricesFilter{product: product{Model: "iPhone 17 Pro Max"}},
+			expectedTitles: []string{"🔍 iPhone 17 Pro Max Cosmic Orange", "🔍 iPhone 17 Pro Max Deep Blue"},
+		},
+		{
+			testName:       "by model keeping esim",
+			filter:         pricesFilter{Esim: &esim},
+			expectedTitles: []string{"🔍 iPhone 17", "🔍 iPhone 17 Pro Max"},
+		},
+		{
+			testName:       "by color when one model is left",
+			filter:         pricesFilter{product: product{Capacity: 1024}},
+			expectedTitles: nil,
+		},
+		{
+			testName:       "nothing left to narrow",
+			filter:         pricesFilter{product: product{Model: "iPhone 17 Pro"}},
+			expectedTitles: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			matched := []models.IPhone{}
+			for _, iphone := range pricesIPhones {
+				if tt.filter.matches(iphone) {
+					matched = append(matched, iphone)
+				}
+			}
+			var titles []string
+			for _, next := range tt.filter.drillDown(matched) {
+				assert.Equal(t, tt.filter.Esim, next.Esim)
+				titles = append(titles, next.title())
+			}
+			assert.Equal(t, tt.expectedTitles, titles)
+		})
+	}
+}
+
+func TestPricesFilter_CallbackRoundTrip(t *testing.T) {
+	esim, sim := true, false
+	filters := []pricesFilter{
+		{product: product{Model: "iPhone 17"}},
+		{product: product{Model: "iPhone 17 Pro", ColorName: "Cosmic Orange"}},
+		{product: product{Model: "iPhone 17 Pro Max", ColorName: "Cosmic Orange", Capacity: 1024}, Esim: &esim},
+		{product: product{Model: "iPhone 17 Pro Max", ColorName: "Deep Blue", Capacity: 256}, Esim: &sim},
+	}
+	for _, filter := range filters {
+		t.Run(filter.title(), func(t *testing.T) {
+			data := strings.Join(filter.args(), " ")
+			assert.True(t, fitsCallback("prices_drill", data), "callback data %q is too long", data)
+			parsed, err := parsePricesFilter(strings.Fields(data), pricesIPhones)
+			assert.NoError(t, err)
+			assert.Equal(t, filter, parsed)
+		})
+	}
+}
+
+func TestFitsCallback(t *testing.T) {
+	assert.True(t, fitsCallback("prices_drill", strings.Repeat("a", callbackLimit-len("\fprices_drill|"))))
+	assert.False(t, fitsCallback("prices_drill", strings.Repeat("a", callbackLimit-len("\fprices_drill|")+1)))
+	assert.True(t, fitsCallback("alert_set", "12", "2800"))
+	assert.False(t, fitsCallback("alert_new_color", "iPhone 17", "Red|Blue"))
+}
@@ -0,0 +1,265 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	telebot "gopkg.in/telebot.v4"
+)
+
+const maxNameLength = 64
+
+var skipEmail = telebot.Btn{Unique: "signup_skip_email", Text: "пропустить"}
+
+func (tb *telegramBot) registerUser() {
+	op := place + "registerUser"
+	log := tb.Logger.AddOp(op)
+	yes := telebot.Btn{Unique: "store_chatid_yes", Text: "✅ да"}
+	no := telebot.Btn{Unique: "store_chatid_no", Text: "❌ нет"}
+
+	tb.Bot.Handle("/start", func(c telebot.Context) error {
+		if code := strings.TrimSpace(c.Message().Payload); code != "" {
+			return tb.linkAccount(c, code)
+		}
+		sender, chatId := c.Sender(), c.Chat().ID
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		_, err := tb.UserRepository.CheckChatId(ctx, op, sender.ID, chatId)
+		if errors.Is(err, errs.ErrNotFoundBase) {
+			ok, err := tb.fire(ctx, chatId, signupStarted)
+			if err != nil {
+				log.Error("failed to change chat state", logger.Err(err))
+				return c.Send("произошла ошибка((")
+			}
+			if !ok {
+				return nil
+			}
+			return c.Send("привет! давай знакомиться, как тебя зовут?")
+		}
+		if err != nil {
+			log.Error("failed to check chat id", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		ok, err := tb.fire(ctx, chatId, startAsked)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		if !ok {
+			return nil
+		}
+		markup := &telebot.ReplyMarkup{}
+		markup.Inline(markup.Row(yes, no))
+		return c.Send("хотите получать обновления цены айфончика 17??", markup)
+	})
+	tb.Bot.Handle(&yes, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		ok, err := tb.fire(ctx, chatId, startAnswered)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if !ok {
+			return nil
+		}
+		if err := tb.UserRepository.SetChatId(ctx, c.Sender().ID, chatId); err != nil {
+			if errors.Is(err, errs.ErrAlreadyExistsBase) {
+				return c.Edit("вы уже получаете обновления")
+			}
+			log.Error("failed to set chat id", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		return c.Edit("ждите обновления))")
+	})
+	tb.Bot.Handle(&no, func(c telebot.Context) error {
+		chatId := c.Chat().ID
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		ok, err := tb.fire(ctx, chatId, startAnswered)
+		if err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if !ok {
+			return nil
+		}
+		if err := tb.UserRepository.DropChatId(ctx, c.Sender().ID, chatId); err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Edit("вы не подписаны на обновления")
+			}
+			log.Error("failed to delete chat id", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		return c.Edit("обновлений не ждите((")
+	})
+	tb.Bot.Handle(&skipEmail, func(c telebot.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		state, name, err := tb.chatState(ctx, c.Chat().ID)
+		if err != nil {
+			log.Error("failed to receive chat state", logger.Err(err))
+			return c.Edit("произошла ошибка((")
+		}
+		if state != askingEmail {
+			return nil
+		}
+		if err := c.Edit("email можно будет добавить позже"); err != nil {
+			log.Error("failed to edit message", logger.Err(err))
+		}
+		return tb.signUp(c, name, "")
+	})
+}
+
+// linkAccount binds the chat to a user registered through the api by the code issued on registration.
+func (tb *telegramBot) linkAccount(c telebot.Context, code string) error {
+	op := place + "linkAccount"
+	log := tb.Logger.AddOp(op)
+	chatId := c.Chat().ID
+	ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+	defer cancel()
+	name, err := tb.UserRepository.LinkByCode(ctx, strings.ToUpper(code), c.Sender().ID, chatId)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFoundBase) {
+			return c.Send("❌ код не найден или устарел, зарегистрируйтесь еще раз или просто отправьте /start")
+		}
+		if errors.Is(err, errs.ErrAlreadyExistsBase) {
+			return c.Send("❌ этот телеграм уже привязан к другому аккаунту")
+		}
+		log.Error("failed to link account", logger.Err(err))
+		return c.Send("произошла ошибка((")
+	}
+	if err := tb.ChatStateRepository.Delete(ctx, chatId); err != nil {
+		log.Error("failed to reset chat state", logger.Err(err))
+	}
+	log.Info("account linked", "chat_id", chatId)
+	return c.Send(fmt.Sprintf("✅ аккаунт привязан, %s! ждите обновления))\nцены сейчас: /prices\nалерты: /alert", name))
+}
+
+// issueApiToken replaces the api token of the sender, the old one stops working.
+func (tb *telegramBot) issueApiToken() {
+	op := place + "issueApiToken"
+	log := tb.Logger.AddOp(op)
+	tb.Bot.Handle("/token", func(c telebot.Context) error {
+		if c.Chat().Type != telebot.ChatPrivate {
+			return c.Send("токен выдаю только в личке")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+		defer cancel()
+		user, err := tb.UserRepository.FetchByTelegramId(ctx, c.Sender().ID)
+		if err != nil {
+			if errors.Is(err, errs.ErrNotFoundBase) {
+				return c.Send("сначала зарегистрируйтесь: /start")
+			}
+			log.Error("failed to fetch user", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		token := utils.NewApiToken()
+		if err := tb.UserRepository.SetApiToken(ctx, user.Id, utils.HashApiToken(token)); err != nil {
+			log.Error("failed to set api token", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		return c.Send(fmt.Sprintf("🔑 новый api токен, старый больше не работает:\n`%s`\nпередавайте его в заголовке `Authorization: Bearer <токен>`", token), telebot.ModeMarkdown)
+	})
+}
+
+func (tb *telegramBot) enterName(c telebot.Context) error {
+	op := place + "enterName"
+	log := tb.Logger.AddOp(op)
+	name := strings.TrimSpace(c.Text())
+	if name == "" || strings.HasPrefix(name, "/") || utf8.RuneCountInString(name) > maxNameLength {
+		return c.Send(fmt.Sprintf("❌ имя должно быть от 1 до %d символов, попробуй еще раз", maxNameLength))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+	defer cancel()
+	ok, err := tb.fireWith(ctx, c.Chat().ID, nameEntered, name)
+	if err != nil {
+		log.Error("failed to change chat state", logger.Err(err))
+		return c.Send("произошла ошибка((")
+	}
+	if !ok {
+		return nil
+	}
+	markup := &telebot.ReplyMarkup{}
+	markup.Inline(markup.Row(skipEmail))
+	return c.Send(fmt.Sprintf("приятно познакомиться, %s!\nнапиши email, если хочешь получать цены и на почту, или нажми «пропустить»", name), markup)
+}
+
+func (tb *telegramBot) enterEmail(c telebot.Context, name string) error {
+	email := strings.TrimSpace(c.Text())
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return c.Send("❌ неправильный email, попробуй еще раз или нажми «пропустить»")
+	}
+	return tb.signUp(c, name, email)
+}
+
+// signUp creates the user keyed by telegram id and subscribes the chat right away.
+func (tb *telegramBot) signUp(c telebot.Context, name, email string) error {
+	op := place + "signUp"
+	log := tb.Logger.AddOp(op)
+	sender, chatId := c.Sender(), c.Chat().ID
+	ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+	defer cancel()
+	user := &models.User{
+		Id:   uuid.New(),
+		Name: name,
+		Contacts: models.Contacts{
+			Email:      email,
+			TelegramId: &sender.ID,
+			ChatId:     &chatId,
+		},
+	}
+	if sender.Username != "" {
+		user.Telegram = &sender.Username
+	}
+	if err := tb.UserRepository.Create(ctx, user); err != nil {
+		if !errors.Is(err, errs.ErrAlreadyExistsBase) {
+			log.Error("failed to create user", logger.Err(err))
+			return c.Send("произошла ошибка((")
+		}
+		return tb.signUpConflict(c, name, email)
+	}
+	if _, err := tb.fire(ctx, chatId, emailEntered); err != nil {
+		log.Error("failed to change chat state", logger.Err(err))
+	}
+	log.Info("user signed up", "id", user.Id)
+	return c.Send(fmt.Sprintf("✅ готово, %s! ждите обновления))\nцены сейчас: /prices\nалерты: /alert", name))
+}
+
+// signUpConflict links users stored before telegram ids by their chat, any other account has to be linked with a code.
+func (tb *telegramBot) signUpConflict(c telebot.Context, name, email string) error {
+	op := place + "signUpConflict"
+	log := tb.Logger.AddOp(op)
+	sender, chatId := c.Sender(), c.Chat().ID
+	ctx, cancel := context.WithTimeout(context.Background(), tb.Config.Timeout)
+	defer cancel()
+	existing, err := tb.UserRepository.LinkExisting(ctx, sender.ID, chatId)
+	if err != nil && !errors.Is(err, errs.ErrNotFoundBase) {
+		log.Error("failed to link existing user", logger.Err(err))
+		return c.Send("произошла ошибка((")
+	}
+	if err == nil {
+		if _, err := tb.fire(ctx, chatId, emailEntered); err != nil {
+			log.Error("failed to change chat state", logger.Err(err))
+		}
+		log.Info("existing user linked", "chat_id", chatId)
+		return c.Send(fmt.Sprintf("✅ нашли твой аккаунт, %s! ждите обновления))\nцены сейчас: /prices\nалерты: /alert", existing))
+	}
+	if email != "" {
+		markup := &telebot.ReplyMarkup{}
+		markup.Inline(markup.Row(skipEmail))
+		return c.Send(fmt.Sprintf("❌ email %s уже занят, %s, напиши другой или нажми «пропустить»\nесли это твой аккаунт, привяжи его кодом из регистрации: /start <код>", email, name), markup)
+	}
+	return c.Send("❌ этот телеграм уже указан в другом аккаунте, привяжи его кодом из регистрации: /start <код>")
+}
@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"time"
+)
+
+type chatState string
+
+const (
+	idle           chatState = ""
+	storingChatId  chatState = "storing_chat_id"
+	askingForPrice chatState = "asking_for_price"
+	choosingPrice  chatState = "choosing_price"
+	askingName     chatState = "asking_name"
+	askingEmail    chatState = "asking_email"
+)
+
+type chatEvent string
+
+const (
+	startAsked    chatEvent = "start_asked"
+	startAnswered chatEvent = "start_answered"
+	priceAsked    chatEvent = "price_asked"
+	priceAccepted chatEvent = "price_accepted"
+	priceDeclined chatEvent = "price_declined"
+	priceEntered  chatEvent = "price_entered"
+	signupStarted chatEvent = "signup_started"
+	nameEntered   chatEvent = "name_entered"
+	emailEntered  chatEvent = "email_entered"
+)
+
+// transitions lists every event a state accepts, events missing here are ignored.
+var transitions = map[chatState]map[chatEvent]chatState{
+	idle: {
+		startAsked:    storingChatId,
+		signupStarted: askingName,
+		priceAsked:    askingForPrice,
+	},
+	storingChatId: {
+		startAsked:    storingChatId,
+		startAnswered: idle,
+	},
+	askingForPrice: {
+		priceAccepted: choosingPrice,
+		priceDeclined: idle,
+	},
+	choosingPrice: {
+		priceEntered: idle,
+	},
+	askingName: {
+		signupStarted: askingName,
+		nameEntered:   askingEmail,
+	},
+	askingEmail: {
+		signupStarted: askingName,
+		emailEntered:  idle,
+	},
+}
+
+// stateTtl is how long the bot waits for the next step before forgetting the state.
+var stateTtl = map[chatState]time.Duration{
+	storingChatId:  time.Hour,
+	askingForPrice: 15 * time.Minute,
+	choosingPrice:  15 * time.Minute,
+	askingName:     time.Hour,
+	askingEmail:    time.Hour,
+}
+
+// chatState returns the current state of the chat along with the data collected in it.
+func (tb *telegramBot) chatState(ctx context.Context, chatId int64) (chatState, string, error) {
+	op := place + "chatState"
+	state, err := tb.ChatStateRepository.Get(ctx, chatId)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFoundBase) {
+			return idle, "", nil
+		}
+		return idle, "", errs.NewAppError(op, err)
+	}
+	return chatState(state.State), state.Data, nil
+}
+
+// fire moves the chat to the state the event leads to and reports whether the current state accepted it.
+func (tb *telegramBot) fire(ctx context.Context, chatId int64, event chatEvent) (bool, error) {
+	return tb.fireWith(ctx, chatId, event, "")
+}
+
+// fireWith is fire that keeps data for the next state.
+func (tb *telegramBot) fireWith(ctx context.Context, chatId int64, event chatEvent, data string) (bool, error) {
+	op := place + "fireWith"
+	current, _, err := tb.chatState(ctx, chatId)
+	if err != nil {
+		return false, errs.NewAppError(op, err)
+	}
+	next, ok := transitions[current][event]
+	if !ok {
+		return false, nil
+	}
+	if next == idle {
+		if err := tb.ChatStateRepository.Delete(ctx, chatId); err != nil {
+			return false, errs.NewAppError(op, err)
+		}
+		return true, nil
+	}
+	state := models.ChatState{ChatId: chatId, State: string(next), Data: data, ExpiresAt: time.Now().Add(stateTtl[next])}
+	if err := tb.ChatStateRepository.Set(ctx, state); err != nil {
+		return false, errs.NewAppError(op, err)
+	}
+	return true, nil
+}
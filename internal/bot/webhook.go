@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"iFall/internal/config"
+	"iFall/pkg/logger"
+
+	telebot "gopkg.in/telebot.v4"
+)
+
+const (
+	ModePoller  = "poller"
+	ModeWebhook = "webhook"
+)
+
+// webhookPoller only registers the webhook, updates are pushed by the server through HandleUpdate.
+type webhookPoller struct {
+	Config config.TelegramBotConfig
+	Logger *logger.Logger
+}
+
+func (wp *webhookPoller) Poll(b *telebot.Bot, _ chan telebot.Update, stop chan struct{}) {
+	op := place + "webhookPoller.Poll"
+	log := wp.Logger.AddOp(op)
+	webhook := &telebot.Webhook{
+		SecretToken: wp.Config.WebhookSecret,
+		Endpoint:    &telebot.WebhookEndpoint{PublicURL: wp.Config.WebhookUrl},
+	}
+	if err := b.SetWebhook(webhook); err != nil {
+		log.Error("failed to set webhook", logger.Err(err))
+	} else {
+		log.Info("webhook set", "url", wp.Config.WebhookUrl)
+	}
+	<-stop
+}
+
+func (tb *telegramBot) HandleUpdate(update telebot.Update) {
+	tb.Bot.ProcessUpdate(update)
+}
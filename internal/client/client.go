@@ -6,39 +6,40 @@ import (
 	"iFall/internal/domain/models"
 	"iFall/pkg/errs"
 	"net/http"
-	"strconv"
-	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 //go:generate mockgen -source=client.go -destination=mocks/client-mock.go
 type ApiClient interface {
-	GetIPhoneData(id string) (*models.IPhone, error)
+	GetIPhoneData(url string) (*models.IPhone, error)
 }
 
 type apiClient struct {
-	Client  *http.Client
-	BaseURL string
+	Client   *http.Client
+	Registry *Registry
 }
 
-func NewClient(cfg config.ApiClientConfig) ApiClient {
+func NewClient(cfg config.ApiClientConfig, sources ...Source) ApiClient {
 	client := http.Client{
 		Timeout: cfg.Timeout,
 	}
 	return &apiClient{
-		Client:  &client,
-		BaseURL: cfg.BaseURL,
+		Client:   &client,
+		Registry: NewRegistry(sources...),
 	}
 }
 
-func (ac *apiClient) GetIPhoneData(id string) (*models.IPhone, error) {
+func (ac *apiClient) GetIPhoneData(url string) (*models.IPhone, error) {
 	op := "apiClient.GetIPhoneData"
-	url := fmt.Sprintf("%s/%s", ac.BaseURL, id)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, errs.NewAppError(op, err)
 	}
+	source, ok := ac.Registry.Lookup(req.URL.Hostname())
+	if !ok {
+		return nil, errs.NewAppError(op, fmt.Errorf("%w: %s", ErrUnknownSource, req.URL.Hostname()))
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Go-http-client)")
 	resp, err := ac.Client.Do(req)
 	if err != nil {
@@ -52,20 +53,10 @@ func (ac *apiClient) GetIPhoneData(id string) (*models.IPhone, error) {
 	if err != nil {
 		return nil, errs.NewAppError(op, err)
 	}
-	name := strings.TrimSpace(doc.Find(`h1[itemprop="name"]`).First().Text())
-	priceSel := doc.Find(".price-block .price:not(.old)").First()
-	rawPrice := strings.TrimSpace(priceSel.Text())
-	strPriceWithoutSpaces := strings.ReplaceAll(rawPrice, " ", "")
-	fStrPrice := strings.ReplaceAll(strPriceWithoutSpaces, "/", "")
-	sStrPrice := strings.ReplaceAll(fStrPrice, "\\", "")
-	floatPrice, err := strconv.ParseFloat(sStrPrice, 32)
+	iphone, err := source.Parse(doc)
 	if err != nil {
 		return nil, errs.NewAppError(op, err)
 	}
-	price := floatPrice / 100
-	iphone := &models.IPhone{
-		Name:  name,
-		Price: price,
-	}
+	iphone.Source = source.Name()
 	return iphone, nil
 }
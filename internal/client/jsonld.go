@@ -0,0 +1,281 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"iFall/internal/domain/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func jsonLdDocuments(doc *goquery.Document) []any {
+	docs := []any{}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return
+		}
+		docs = append(docs, flattenJsonLd(data)...)
+	})
+	return docs
+}
+
+func flattenJsonLd(data any) []any {
+	switch v := data.(type) {
+	case []any:
+		nodes := []any{}
+		for _, item := range v {
+			nodes = append(nodes, flattenJsonLd(item)...)
+		}
+		return nodes
+	case map[string]any:
+		if graph, ok := v["@graph"]; ok {
+			return flattenJsonLd(graph)
+		}
+		return []any{v}
+	}
+	return nil
+}
+
+func jsonLdValue(doc *goquery.Document, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	for _, node := range jsonLdDocuments(doc) {
+		if value, ok := walkJsonLd(node, keys); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func walkJsonLd(node any, keys []string) (any, bool) {
+	if len(keys) == 0 {
+		return node, node != nil
+	}
+	switch v := node.(type) {
+	case map[string]any:
+		child, ok := v[keys[0]]
+		if !ok {
+			return nil, false
+		}
+		return walkJsonLd(child, keys[1:])
+	case []any:
+		if i, err := strconv.Atoi(keys[0]); err == nil {
+			if i < 0 || i >= len(v) {
+				return nil, false
+			}
+			return walkJsonLd(v[i], keys[1:])
+		}
+		for _, item := range v {
+			if value, ok := walkJsonLd(item, keys); ok {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func parseJsonLdPrice(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", "."), 64)
+	}
+	return 0, fmt.Errorf("%w: unexpected json-ld value %v", ErrPriceNotFound, value)
+}
+
+type structuredOffer struct {
+	Name         string
+	Price        float64
+	OldPrice     float64
+	Currency     string
+	Availability string
+	Sku          string
+}
+
+func jsonLdProduct(doc *goquery.Document) (*structuredOffer, bool) {
+	for _, node := range jsonLdDocuments(doc) {
+		product, ok := findJsonLdType(node, "Product")
+		if !ok {
+			continue
+		}
+		offer, ok := productOffer(product)
+		if !ok {
+			continue
+		}
+		offer.Name = jsonLdString(product["name"])
+		if offer.Sku == "" {
+			offer.Sku = jsonLdString(product["sku"])
+		}
+		return offer, true
+	}
+	return nil, false
+}
+
+func findJsonLdType(node any, typ string) (map[string]any, bool) {
+	switch v := node.(type) {
+	case map[string]any:
+		if hasJsonLdType(v, typ) {
+			return v, true
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if found, ok := findJsonLdType(v[key], typ); ok {
+				return found, true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if found, ok := findJsonLdType(item, typ); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func hasJsonLdType(node map[string]any, typ string) bool {
+	switch t := node["@type"].(type) {
+	case string:
+		return t == typ
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func productOffer(product map[string]any) (*structuredOffer, bool) {
+	offers := []map[string]any{}
+	switch v := product["offers"].(type) {
+	case map[string]any:
+		offers = append(offers, v)
+		if nested, ok := v["offers"].([]any); ok {
+			for _, item := range nested {
+				if o, ok := item.(map[string]any); ok {
+					offers = append(offers, o)
+				}
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if o, ok := item.(map[string]any); ok {
+				offers = append(offers, o)
+			}
+		}
+	}
+	for _, o := range offers {
+		currency := o["priceCurrency"]
+		raw, ok := o["price"]
+		if !ok {
+			raw, ok = o["lowPrice"]
+		}
+		var oldPrice float64
+		for _, spec := range priceSpecifications(o) {
+			if isListPrice(spec) {
+				oldPrice, _ = parseJsonLdPrice(spec["price"])
+				continue
+			}
+			if !ok {
+				raw, ok = spec["price"]
+				if currency == nil {
+					currency = spec["priceCurrency"]
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		price, err := parseJsonLdPrice(raw)
+		if err != nil || price <= 0 {
+			continue
+		}
+		offer := &structuredOffer{
+			Price:    price,
+			Currency: strings.ToUpper(jsonLdString(currency)),
+		}
+		if oldPrice > price {
+			offer.OldPrice = oldPrice
+		}
+		fillOfferDetails(offer, offers)
+		return offer, true
+	}
+	for _, o := range offers {
+		availability := normalizeAvailability(jsonLdString(o["availability"]))
+		if availability != models.AvailabilityOutOfStock {
+			continue
+		}
+		offer := &structuredOffer{
+			Currency:     strings.ToUpper(jsonLdString(o["priceCurrency"])),
+			Availability: availability,
+		}
+		fillOfferDetails(offer, offers)
+		return offer, true
+	}
+	return nil, false
+}
+
+func priceSpecifications(offer map[string]any) []map[string]any {
+	specs := []map[string]any{}
+	switch v := offer["priceSpecification"].(type) {
+	case map[string]any:
+		specs = append(specs, v)
+	case []any:
+		for _, item := range v {
+			if spec, ok := item.(map[string]any); ok {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	return specs
+}
+
+func isListPrice(spec map[string]any) bool {
+	priceType := jsonLdString(spec["priceType"])
+	priceType = priceType[strings.LastIndexAny(priceType, "/:")+1:]
+	return priceType == "ListPrice" || priceType == "StrikethroughPrice"
+}
+
+func fillOfferDetails(offer *structuredOffer, offers []map[string]any) {
+	for _, candidate := range offers {
+		if offer.Availability == "" {
+			offer.Availability = normalizeAvailability(jsonLdString(candidate["availability"]))
+		}
+		if offer.Sku == "" {
+			offer.Sku = jsonLdString(candidate["sku"])
+		}
+	}
+}
+
+func jsonLdString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func normalizeAvailability(value string) string {
+	value = value[strings.LastIndexAny(value, "/:")+1:]
+	switch strings.ToLower(value) {
+	case "instock", "limitedavailability", "onlineonly", "instoreonly":
+		return models.AvailabilityInStock
+	case "preorder", "presale", "backorder":
+		return models.AvailabilityPreorder
+	case "outofstock", "soldout", "discontinued":
+		return models.AvailabilityOutOfStock
+	}
+	return ""
+}
@@ -35,16 +35,16 @@ func (m *MockApiClient) EXPECT() *MockApiClientMockRecorder {
 }
 
 // GetIPhoneData mocks base method.
-func (m *MockApiClient) GetIPhoneData(id string) (*models.IPhone, error) {
+func (m *MockApiClient) GetIPhoneData(url string) (*models.IPhone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIPhoneData", id)
+	ret := m.ctrl.Call(m, "GetIPhoneData", url)
 	ret0, _ := ret[0].(*models.IPhone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetIPhoneData indicates an expected call of GetIPhoneData.
-func (mr *MockApiClientMockRecorder) GetIPhoneData(id interface{}) *gomock.Call {
+func (mr *MockApiClientMockRecorder) GetIPhoneData(url interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIPhoneData", reflect.TypeOf((*MockApiClient)(nil).GetIPhoneData), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIPhoneData", reflect.TypeOf((*MockApiClient)(nil).GetIPhoneData), url)
 }
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+var (
+	ErrInvalidRules  = errors.New("invalid scraping rules")
+	ErrPriceNotFound = errors.New("price not found")
+)
+
+type ruleSource struct {
+	Rules            config.SourceRulesConfig
+	PriceRegex       *regexp.Regexp
+	InstallmentRegex *regexp.Regexp
+}
+
+func NewRuleSource(rules config.SourceRulesConfig) (Source, error) {
+	if strings.TrimSpace(rules.Name) == "" {
+		return nil, fmt.Errorf("%w: name is empty", ErrInvalidRules)
+	}
+	if len(rules.Hosts) == 0 {
+		return nil, fmt.Errorf("%w: %s: no hosts", ErrInvalidRules, rules.Name)
+	}
+	for _, host := range rules.Hosts {
+		if strings.TrimSpace(host) == "" {
+			return nil, fmt.Errorf("%w: %s: empty host", ErrInvalidRules, rules.Name)
+		}
+	}
+	for _, selector := range []string{rules.NameSelector, rules.PriceSelector, rules.OldPriceSelector, rules.InstallmentSelector, rules.OutOfStockSelector, rules.PreorderSelector} {
+		if selector == "" {
+			continue
+		}
+		if _, err := cascadia.Compile(selector); err != nil {
+			return nil, fmt.Errorf("%w: %s: selector %q: %v", ErrInvalidRules, rules.Name, selector, err)
+		}
+	}
+	if rules.Divisor < 0 {
+		return nil, fmt.Errorf("%w: %s: negative divisor", ErrInvalidRules, rules.Name)
+	}
+	if rules.Divisor == 0 {
+		rules.Divisor = 1
+	}
+	if rules.DecimalSeparator == "" {
+		rules.DecimalSeparator = "."
+	}
+	if rules.DecimalSeparator == rules.ThousandsSeparator {
+		return nil, fmt.Errorf("%w: %s: decimal and thousands separators are equal", ErrInvalidRules, rules.Name)
+	}
+	rs := &ruleSource{
+		Rules: rules,
+	}
+	var err error
+	if rs.PriceRegex, err = compileAmountRegex(rules.PriceRegex); err != nil {
+		return nil, fmt.Errorf("%w: %s: price regex: %v", ErrInvalidRules, rules.Name, err)
+	}
+	if rs.InstallmentRegex, err = compileAmountRegex(rules.InstallmentRegex); err != nil {
+		return nil, fmt.Errorf("%w: %s: installment regex: %v", ErrInvalidRules, rules.Name, err)
+	}
+	return rs, nil
+}
+
+func compileAmountRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() > 1 {
+		return nil, errors.New("more than one group")
+	}
+	return re, nil
+}
+
+func MustRuleSources(rules []config.SourceRulesConfig) []Source {
+	sources := make([]Source, 0, len(rules))
+	names := map[string]bool{}
+	for _, r := range rules {
+		source, err := NewRuleSource(r)
+		if err != nil {
+			panic(err)
+		}
+		if names[source.Name()] {
+			panic(fmt.Errorf("%w: duplicate source %s", ErrInvalidRules, source.Name()))
+		}
+		names[source.Name()] = true
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+func (rs *ruleSource) Name() string {
+	return rs.Rules.Name
+}
+
+func (rs *ruleSource) Hosts() []string {
+	return rs.Rules.Hosts
+}
+
+func (rs *ruleSource) Parse(doc *goquery.Document) (*models.IPhone, error) {
+	iphone := &models.IPhone{
+		Currency: rs.Rules.Currency,
+	}
+	if rs.Rules.NameSelector != "" {
+		iphone.Name = strings.TrimSpace(doc.Find(rs.Rules.NameSelector).First().Text())
+	}
+	if offer, ok := jsonLdProduct(doc); ok {
+		iphone.Price = offer.Price
+		iphone.OldPrice = offer.OldPrice
+		iphone.Availability = offer.Availability
+		iphone.Sku = offer.Sku
+		if offer.Currency != "" {
+			iphone.Currency = offer.Currency
+		}
+		if iphone.Name == "" {
+			iphone.Name = offer.Name
+		}
+	}
+	if rs.Rules.JsonLdPath != "" {
+		if value, ok := jsonLdValue(doc, rs.Rules.JsonLdPath); ok {
+			if price, err := parseJsonLdPrice(value); err == nil {
+				iphone.Price = price
+			}
+		}
+	}
+	if iphone.Availability == "" {
+		iphone.Availability = rs.parseAvailability(doc)
+	}
+	if iphone.Price == 0 && rs.Rules.PriceSelector != "" {
+		price, err := rs.parsePrice(doc.Find(rs.Rules.PriceSelector).First().Text())
+		if err != nil && iphone.Availability != models.AvailabilityOutOfStock {
+			return nil, err
+		}
+		iphone.Price = price
+	}
+	if iphone.Price == 0 && iphone.Availability != models.AvailabilityOutOfStock {
+		return nil, fmt.Errorf("%w: no structured data", ErrPriceNotFound)
+	}
+	if iphone.OldPrice == 0 && rs.Rules.OldPriceSelector != "" {
+		if oldPrice, err := rs.parsePrice(doc.Find(rs.Rules.OldPriceSelector).First().Text()); err == nil && oldPrice > iphone.Price {
+			iphone.OldPrice = oldPrice
+		}
+	}
+	if rs.Rules.InstallmentSelector != "" {
+		raw := doc.Find(rs.Rules.InstallmentSelector).First().Text()
+		if installment, err := rs.parseAmount(raw, rs.InstallmentRegex); err == nil {
+			iphone.Installment = installment
+		}
+	}
+	return iphone, nil
+}
+
+func (rs *ruleSource) parseAvailability(doc *goquery.Document) string {
+	if rs.Rules.OutOfStockSelector != "" && doc.Find(rs.Rules.OutOfStockSelector).Length() > 0 {
+		return models.AvailabilityOutOfStock
+	}
+	if rs.Rules.PreorderSelector != "" && doc.Find(rs.Rules.PreorderSelector).Length() > 0 {
+		return models.AvailabilityPreorder
+	}
+	return models.AvailabilityInStock
+}
+
+func (rs *ruleSource) parsePrice(raw string) (float64, error) {
+	return rs.parseAmount(raw, rs.PriceRegex)
+}
+
+func (rs *ruleSource) parseAmount(raw string, re *regexp.Regexp) (float64, error) {
+	value := strings.TrimSpace(raw)
+	if re != nil {
+		match := re.FindStringSubmatch(value)
+		if match == nil {
+			return 0, fmt.Errorf("%w: %q", ErrPriceNotFound, raw)
+		}
+		value = match[len(match)-1]
+	}
+	if rs.Rules.ThousandsSeparator != "" {
+		value = strings.ReplaceAll(value, rs.Rules.ThousandsSeparator, "")
+	}
+	value = strings.ReplaceAll(value, rs.Rules.DecimalSeparator, ".")
+	value = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' {
+			return r
+		}
+		return -1
+	}, value)
+	if value == "" {
+		return 0, fmt.Errorf("%w: %q", ErrPriceNotFound, raw)
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return price / rs.Rules.Divisor, nil
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSource_Parse(t *testing.T) {
+	newtonRules := config.SourceRulesConfig{
+		Name:               "newton.by",
+		Hosts:              []string{"newton.by"},
+		NameSelector:       `h1[itemprop="name"]`,
+		PriceSelector:      ".price-block .price:not(.old)",
+		ThousandsSeparator: " ",
+		Divisor:            100,
+		Currency:           "BYN",
+	}
+	tests := []struct {
+		testName             string
+		rules                config.SourceRulesConfig
+		html                 string
+		expectedName         string
+		expectedPrice        float64
+		expectedCurrency     string
+		expectedAvailability string
+		expectedSku          string
+		expectedOldPrice     float64
+		expectedInstallment  float64
+		expectedError        error
+	}{
+		{
+			testName:             "success css selectors",
+			rules:                newtonRules,
+			html:                 `<h1 itemprop="name"> iPhone 17 </h1><div class="price-block"><span class="price old">4 000/00</span><span class="price">3 191/30</span></div>`,
+			expectedName:         "iPhone 17",
+			expectedPrice:        3191.3,
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName:             "success structured data",
+			rules:                newtonRules,
+			html:                 `<h1 itemprop="name">iPhone 17</h1><script type="application/ld+json">{"@context":"https://schema.org","@type":"Product","name":"Apple iPhone 17","sku":"MG6J4","offers":{"@type":"Offer","price":3099.5,"priceCurrency":"byn","availability":"https://schema.org/InStock"}}</script><div class="price-block"><span class="price">3 191/30</span></div>`,
+			expectedName:         "iPhone 17",
+			expectedPrice:        3099.5,
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityInStock,
+			expectedSku:          "MG6J4",
+			expectedError:        nil,
+		},
+		{
+			testName:             "success structured aggregate offer",
+			rules:                config.SourceRulesConfig{Name: "shop.by", Hosts: []string{"shop.by"}},
+			html:                 `<script type="application/ld+json">[{"@type":"BreadcrumbList"},{"@type":["Product"],"name":"iPhone 17 Pro","offers":{"@type":"AggregateOffer","lowPrice":"4199.00","priceCurrency":"BYN","offers":[{"@type":"Offer","sku":"MG8A4","availability":"PreOrder"}]}}]</script>`,
+			expectedName:         "iPhone 17 Pro",
+			expectedPrice:        4199,
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityPreorder,
+			expectedSku:          "MG8A4",
+			expectedError:        nil,
+		},
+		{
+			testName:             "broken structured data falls back to css",
+			rules:                newtonRules,
+			html:                 `<script type="application/ld+json">{"@type":"Product",</script><div class="price-block"><span class="price">1 500/00</span></div>`,
+			expectedPrice:        1500,
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName: "out of stock selector without price",
+			rules: config.SourceRulesConfig{
+				Name:               "newton.by",
+				Hosts:              []string{"newton.by"},
+				PriceSelector:      ".price-block .price:not(.old)",
+				OutOfStockSelector: ".not-available",
+			},
+			html:                 `<div class="not-available">Нет в наличии</div>`,
+			expectedPrice:        0,
+			expectedAvailability: models.AvailabilityOutOfStock,
+			expectedError:        nil,
+		},
+		{
+			testName:             "structured out of stock without price",
+			rules:                config.SourceRulesConfig{Name: "shop.by", Hosts: []string{"shop.by"}},
+			html:                 `<script type="application/ld+json">{"@type":"Product","name":"iPhone 17","offers":{"@type":"Offer","priceCurrency":"BYN","availability":"http://schema.org/OutOfStock"}}</script>`,
+			expectedName:         "iPhone 17",
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityOutOfStock,
+			expectedError:        nil,
+		},
+		{
+			testName: "success regex and comma decimal",
+			rules: config.SourceRulesConfig{
+				Name:               "shop.by",
+				Hosts:              []string{"shop.by"},
+				PriceSelector:      ".cost",
+				PriceRegex:         `Цена: ([0-9.,]+)`,
+				DecimalSeparator:   ",",
+				ThousandsSeparator: ".",
+			},
+			html:                 `<div class="cost">Цена: 3.191,30 BYN</div>`,
+			expectedPrice:        3191.3,
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName: "success json-ld path",
+			rules: config.SourceRulesConfig{
+				Name:          "shop.by",
+				Hosts:         []string{"shop.by"},
+				PriceSelector: ".cost",
+				JsonLdPath:    "offers.price",
+			},
+			html:                 `<script type="application/ld+json">{"@graph":[{"@type":"Product","offers":[{"price":"2999.90"}]}]}</script><div class="cost">1</div>`,
+			expectedPrice:        2999.9,
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName: "json-ld missing falls back to css",
+			rules: config.SourceRulesConfig{
+				Name:          "shop.by",
+				Hosts:         []string{"shop.by"},
+				PriceSelector: ".cost",
+				JsonLdPath:    "offers.price",
+			},
+			html:                 `<div class="cost">1 500</div>`,
+			expectedPrice:        1500,
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName: "success old price and installment",
+			rules: config.SourceRulesConfig{
+				Name:                "newton.by",
+				Hosts:               []string{"newton.by"},
+				PriceSelector:       ".price-block .price:not(.old)",
+				OldPriceSelector:    ".price-block .price.old",
+				InstallmentSelector: ".installment",
+				InstallmentRegex:    `от ([0-9 /]+)`,
+				ThousandsSeparator:  " ",
+				Divisor:             100,
+			},
+			html:                 `<div class="price-block"><span class="price old">3 500/00</span><span class="price">3 080/00</span></div><div class="installment">Рассрочка от 128/34 руб./мес.</div>`,
+			expectedPrice:        3080,
+			expectedOldPrice:     3500,
+			expectedInstallment:  128.34,
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName:             "structured list price",
+			rules:                config.SourceRulesConfig{Name: "shop.by", Hosts: []string{"shop.by"}},
+			html:                 `<script type="application/ld+json">{"@type":"Product","offers":{"@type":"Offer","priceCurrency":"BYN","priceSpecification":[{"@type":"UnitPriceSpecification","price":"3080.00"},{"@type":"UnitPriceSpecification","priceType":"https://schema.org/ListPrice","price":"3500.00"}]}}</script>`,
+			expectedPrice:        3080,
+			expectedOldPrice:     3500,
+			expectedCurrency:     "BYN",
+			expectedAvailability: models.AvailabilityInStock,
+			expectedError:        nil,
+		},
+		{
+			testName:      "price not found",
+			rules:         newtonRules,
+			html:          `<h1 itemprop="name">iPhone 17</h1>`,
+			expectedError: ErrPriceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			source, err := NewRuleSource(tt.rules)
+			assert.NoError(t, err)
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			assert.NoError(t, err)
+			iphone, err := source.Parse(doc)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedName, iphone.Name)
+				assert.InDelta(t, tt.expectedPrice, iphone.Price, 0.001)
+				assert.Equal(t, tt.expectedCurrency, iphone.Currency)
+				assert.Equal(t, tt.expectedAvailability, iphone.Availability)
+				assert.Equal(t, tt.expectedSku, iphone.Sku)
+				assert.InDelta(t, tt.expectedOldPrice, iphone.OldPrice, 0.001)
+				assert.InDelta(t, tt.expectedInstallment, iphone.Installment, 0.001)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestNewRuleSource(t *testing.T) {
+	tests := []struct {
+		testName      string
+		rules         config.SourceRulesConfig
+		expectedError error
+	}{
+		{
+			testName:      "success",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}, PriceSelector: ".price"},
+			expectedError: nil,
+		},
+		{
+			testName:      "empty name",
+			rules:         config.SourceRulesConfig{Hosts: []string{"newton.by"}, PriceSelector: ".price"},
+			expectedError: ErrInvalidRules,
+		},
+		{
+			testName:      "no hosts",
+			rules:         config.SourceRulesConfig{Name: "newton.by", PriceSelector: ".price"},
+			expectedError: ErrInvalidRules,
+		},
+		{
+			testName:      "success structured data only",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}},
+			expectedError: nil,
+		},
+		{
+			testName:      "invalid selector",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}, PriceSelector: ".price[["},
+			expectedError: ErrInvalidRules,
+		},
+		{
+			testName:      "invalid regex",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}, PriceSelector: ".price", PriceRegex: "([0-9"},
+			expectedError: ErrInvalidRules,
+		},
+		{
+			testName:      "equal separators",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}, PriceSelector: ".price", DecimalSeparator: ",", ThousandsSeparator: ","},
+			expectedError: ErrInvalidRules,
+		},
+		{
+			testName:      "negative divisor",
+			rules:         config.SourceRulesConfig{Name: "newton.by", Hosts: []string{"newton.by"}, PriceSelector: ".price", Divisor: -1},
+			expectedError: ErrInvalidRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := NewRuleSource(tt.rules)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"errors"
+	"iFall/internal/domain/models"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var ErrUnknownSource = errors.New("unknown source")
+
+type Source interface {
+	Name() string
+	Hosts() []string
+	Parse(doc *goquery.Document) (*models.IPhone, error)
+}
+
+type Registry struct {
+	sources map[string]Source
+}
+
+func NewRegistry(sources ...Source) *Registry {
+	r := &Registry{
+		sources: map[string]Source{},
+	}
+	for _, s := range sources {
+		r.Register(s)
+	}
+	return r
+}
+
+func (r *Registry) Register(s Source) {
+	for _, host := range s.Hosts() {
+		r.sources[normalizeHost(host)] = s
+	}
+}
+
+func (r *Registry) Lookup(host string) (Source, bool) {
+	s, ok := r.sources[normalizeHost(host)]
+	return s, ok
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
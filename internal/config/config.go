@@ -19,6 +19,9 @@ type Config struct {
 	Scheduler   SchedulerConfig   `mapstructure:"scheduler"`
 	IPhones     IPhonesConfig     `mapstructure:"iphones"`
 	TelegramBot TelegramBotConfig `mapstructure:"telegramBot"`
+	Outbox      OutboxConfig      `mapstructure:"outbox"`
+	Webhook     WebhookConfig     `mapstructure:"webhook"`
+	Chat        ChatConfig        `mapstructure:"chat"`
 }
 
 type AppConfig struct {
@@ -49,8 +52,26 @@ type EmailConfig struct {
 }
 
 type ApiClientConfig struct {
-	BaseURL string        `mapstructure:"baseURL"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	Timeout time.Duration       `mapstructure:"timeout"`
+	Sources []SourceRulesConfig `mapstructure:"sources"`
+}
+
+type SourceRulesConfig struct {
+	Name                string   `mapstructure:"name"`
+	Hosts               []string `mapstructure:"hosts"`
+	NameSelector        string   `mapstructure:"nameSelector"`
+	PriceSelector       string   `mapstructure:"priceSelector"`
+	OldPriceSelector    string   `mapstructure:"oldPriceSelector"`
+	InstallmentSelector string   `mapstructure:"installmentSelector"`
+	InstallmentRegex    string   `mapstructure:"installmentRegex"`
+	OutOfStockSelector  string   `mapstructure:"outOfStockSelector"`
+	PreorderSelector    string   `mapstructure:"preorderSelector"`
+	JsonLdPath          string   `mapstructure:"jsonLdPath"`
+	PriceRegex          string   `mapstructure:"priceRegex"`
+	DecimalSeparator    string   `mapstructure:"decimalSeparator"`
+	ThousandsSeparator  string   `mapstructure:"thousandsSeparator"`
+	Divisor             float64  `mapstructure:"divisor"`
+	Currency            string   `mapstructure:"currency"`
 }
 
 type SchedulerConfig struct {
@@ -60,21 +81,40 @@ type SchedulerConfig struct {
 	EmailSupp  bool `mapstructure:"emailSupp"`
 }
 
+type OutboxConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batchSize"`
+	MaxAttempts int           `mapstructure:"maxAttempts"`
+	BaseBackoff time.Duration `mapstructure:"baseBackoff"`
+	MaxBackoff  time.Duration `mapstructure:"maxBackoff"`
+	SendTimeout time.Duration `mapstructure:"sendTimeout"`
+}
+
+type WebhookConfig struct {
+	Secret  string        `mapstructure:"secret"`
+	Urls    []string      `mapstructure:"urls"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type ChatConfig struct {
+	SlackUrls      []string      `mapstructure:"slackUrls"`
+	DiscordUrls    []string      `mapstructure:"discordUrls"`
+	MattermostUrls []string      `mapstructure:"mattermostUrls"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+}
+
 type IPhonesConfig struct {
-	Black     string        `mapstructure:"black"`
-	White     string        `mapstructure:"white"`
-	Green     string        `mapstructure:"green"`
-	Pink      string        `mapstructure:"pink"`
-	Blue      string        `mapstructure:"blue"`
-	BlackEsim string        `mapstructure:"blackEsim"`
-	BlueEsim  string        `mapstructure:"blueEsim"`
-	PinkEsim  string        `mapstructure:"pinkEsim"`
-	Timeout   time.Duration `mapstructure:"timeout"`
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 type TelegramBotConfig struct {
-	Token   string        `mapstructure:"token"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	Token         string        `mapstructure:"token"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	Mode          string        `mapstructure:"mode"`
+	WebhookUrl    string        `mapstructure:"webhookUrl"`
+	WebhookSecret string        `mapstructure:"webhookSecret"`
+	Username      string        `mapstructure:"username"`
+	LinkCodeTtl   time.Duration `mapstructure:"linkCodeTtl"`
 }
 
 func MustLoad(path string) *Config {
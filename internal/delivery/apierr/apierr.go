@@ -16,6 +16,8 @@ var (
 	ErrRequestTimeout = errors.New("request timeout")
 	ErrToManyRequests = errors.New("to many requests")
 	ErrInvalidJSON    = errors.New("invalid json")
+	ErrNotAcceptable  = errors.New("not acceptable")
+	ErrUnauthorized   = errors.New("unauthorized")
 )
 
 type ApiErr struct {
@@ -79,3 +81,11 @@ func RequestTimeout() ApiErr {
 func TooManyRequests() ApiErr {
 	return NewApiError(fiber.StatusTooManyRequests, ErrToManyRequests)
 }
+
+func NotAcceptable() ApiErr {
+	return NewApiError(fiber.StatusNotAcceptable, ErrNotAcceptable)
+}
+
+func Unauthorized() ApiErr {
+	return NewApiError(fiber.StatusUnauthorized, ErrUnauthorized)
+}
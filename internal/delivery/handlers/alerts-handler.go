@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"iFall/internal/delivery/apierr"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/services"
+	"iFall/internal/dto"
+	"iFall/pkg/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AlertsHandler struct {
+	AlertService services.AlertService
+	Validator    *validator.Validator
+}
+
+func NewAlertsHandler(as services.AlertService, v *validator.Validator) *AlertsHandler {
+	return &AlertsHandler{
+		AlertService: as,
+		Validator:    v,
+	}
+}
+
+func (ah *AlertsHandler) CreateAlert(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	req := dto.CreateAlertRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return apierr.InvalidJSON()
+	}
+	if err := ah.Validator.Validate.Struct(req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if !validAlertThreshold(req.Kind, req.Threshold) {
+		return apierr.InvalidRequest()
+	}
+	alert := models.Alert{
+		IPhoneId:      req.IPhoneId,
+		Model:         req.Model,
+		ColorName:     req.ColorName,
+		Capacity:      req.Capacity,
+		Kind:          req.Kind,
+		Direction:     req.Direction,
+		Threshold:     req.Threshold,
+		WindowHours:   req.WindowHours,
+		Repeat:        req.Repeat,
+		CooldownHours: req.Cooldown,
+	}
+	created, err := ah.AlertService.Create(ctx, contacts, alert)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(created)
+}
+
+func (ah *AlertsHandler) FetchAlerts(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	alerts, err := ah.AlertService.Fetch(ctx, contacts)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"alerts": alerts,
+	})
+}
+
+func (ah *AlertsHandler) DeleteAlert(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return apierr.InvalidRequest()
+	}
+	if err := ah.AlertService.Delete(ctx, contacts, id); err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func validAlertThreshold(kind string, threshold float64) bool {
+	switch kind {
+	case models.AlertKindLow:
+		return true
+	case models.AlertKindDiscount, models.AlertKindDrop:
+		return threshold > 0 && threshold < 100
+	}
+	return threshold > 0
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	mock_services "iFall/internal/domain/services/mocks"
+	"iFall/pkg/errs"
+	"iFall/pkg/server"
+	"iFall/pkg/validator"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertsHandler_CreateAlert(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockAlertService)
+	contact := models.Contacts{Email: "sanya@gmail.com"}
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		request      string
+		expectedCode int
+	}{
+		{
+			testName:     "success",
+			request:      `{"model": "iPhone 17", "color_name": "green", "capacity": 256, "kind": "price", "direction": "below", "threshold": 2800}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				alert := models.Alert{Model: "iPhone 17", ColorName: "green", Capacity: 256, Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800}
+				created := alert
+				created.Id = 1
+				m.EXPECT().Create(gomock.Any(), contact, alert).Return(&created, nil)
+			},
+		},
+		{
+			testName:     "user not found",
+			request:      `{"kind": "discount", "threshold": 10}`,
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				m.EXPECT().Create(gomock.Any(), contact, models.Alert{Kind: models.AlertKindDiscount, Threshold: 10}).Return(nil, errs.ErrNotFound("test-op"))
+			},
+		},
+		{
+			testName:     "success new low without threshold",
+			request:      `{"kind": "low", "window_hours": 720}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				alert := models.Alert{Kind: models.AlertKindLow, WindowHours: 720}
+				m.EXPECT().Create(gomock.Any(), contact, alert).Return(&alert, nil)
+			},
+		},
+		{
+			testName:     "success drop in window",
+			request:      `{"kind": "drop", "threshold": 5, "window_hours": 24}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				alert := models.Alert{Kind: models.AlertKindDrop, Threshold: 5, WindowHours: 24}
+				m.EXPECT().Create(gomock.Any(), contact, alert).Return(&alert, nil)
+			},
+		},
+		{
+			testName:     "success with cooldown",
+			request:      `{"kind": "price", "threshold": 2800, "repeat": "cooldown", "cooldown_hours": 6}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				alert := models.Alert{Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatCooldown, CooldownHours: 6}
+				m.EXPECT().Create(gomock.Any(), contact, alert).Return(&alert, nil)
+			},
+		},
+		{
+			testName:     "failed with unknown repeat",
+			request:      `{"kind": "price", "threshold": 2800, "repeat": "always"}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockAlertService) {},
+		},
+		{
+			testName:     "failed with unknown kind",
+			request:      `{"kind": "vibes", "threshold": 10}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockAlertService) {},
+		},
+		{
+			testName:     "failed with discount over 100",
+			request:      `{"kind": "discount", "threshold": 150}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockAlertService) {},
+		},
+		{
+			testName:     "failed without threshold",
+			request:      `{"kind": "price"}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockAlertService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockAlertService(c)
+			handler := NewAlertsHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Post("/alerts", withContacts(contact), handler.CreateAlert)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("POST", "/alerts", bytes.NewBufferString(tt.request))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestAlertsHandler_DeleteAlert(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockAlertService)
+	contact := models.Contacts{Email: "sanya@gmail.com"}
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		path         string
+		expectedCode int
+	}{
+		{
+			testName:     "success",
+			path:         "/alerts/3",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				m.EXPECT().Delete(gomock.Any(), contact, int64(3)).Return(nil)
+			},
+		},
+		{
+			testName:     "not found",
+			path:         "/alerts/4",
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockAlertService) {
+				m.EXPECT().Delete(gomock.Any(), contact, int64(4)).Return(errs.ErrNotFound("test-op"))
+			},
+		},
+		{
+			testName:     "failed with invalid id",
+			path:         "/alerts/abc",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockAlertService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockAlertService(c)
+			handler := NewAlertsHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Delete("/alerts/:id", withContacts(contact), handler.DeleteAlert)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("DELETE", tt.path, nil)
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"iFall/internal/delivery/apierr"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/services"
+	"iFall/internal/dto"
+	"iFall/pkg/validator"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ChannelsHandler struct {
+	ChannelService services.ChannelService
+	Validator      *validator.Validator
+}
+
+func NewChannelsHandler(cs services.ChannelService, v *validator.Validator) *ChannelsHandler {
+	return &ChannelsHandler{
+		ChannelService: cs,
+		Validator:      v,
+	}
+}
+
+func (ch *ChannelsHandler) FetchChannels(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	channels, err := ch.ChannelService.Fetch(ctx, contacts)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"available": ch.ChannelService.Channels(),
+		"channels":  channels,
+	})
+}
+
+func (ch *ChannelsHandler) SetChannel(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	req := dto.SetChannelRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return apierr.InvalidJSON()
+	}
+	if err := ch.Validator.Validate.Struct(req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if !slices.Contains(ch.ChannelService.Channels(), req.Channel) {
+		return apierr.InvalidRequest()
+	}
+	builtin := req.Channel == models.ChannelEmail || req.Channel == models.ChannelTelegram
+	if !builtin && *req.Enabled && (req.Target == "" || !ch.ChannelService.ValidTarget(req.Channel, req.Target)) {
+		return apierr.InvalidRequest()
+	}
+	channel := models.UserChannel{
+		Channel: req.Channel,
+		Target:  req.Target,
+		Enabled: *req.Enabled,
+	}
+	if err := ch.ChannelService.Set(ctx, contacts, channel); err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
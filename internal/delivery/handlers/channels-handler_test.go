@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	mock_services "iFall/internal/domain/services/mocks"
+	"iFall/pkg/errs"
+	"iFall/pkg/server"
+	"iFall/pkg/validator"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelsHandler_SetChannel(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockChannelService)
+	contact := models.Contacts{Email: "sanya@gmail.com"}
+	available := []string{models.ChannelEmail, models.ChannelTelegram, "pager"}
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		request      string
+		expectedCode int
+	}{
+		{
+			testName:     "success disabling email",
+			request:      `{"channel": "email", "enabled": false}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+				m.EXPECT().Set(gomock.Any(), contact, models.UserChannel{Channel: models.ChannelEmail}).Return(nil)
+			},
+		},
+		{
+			testName:     "success enabling custom channel",
+			request:      `{"channel": "pager", "target": "pager-1", "enabled": true}`,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+				m.EXPECT().ValidTarget("pager", "pager-1").Return(true)
+				m.EXPECT().Set(gomock.Any(), contact, models.UserChannel{Channel: "pager", Target: "pager-1", Enabled: true}).Return(nil)
+			},
+		},
+		{
+			testName:     "failed enabling custom channel with invalid target",
+			request:      `{"channel": "pager", "target": "ftp://pager", "enabled": true}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+				m.EXPECT().ValidTarget("pager", "ftp://pager").Return(false)
+			},
+		},
+		{
+			testName:     "user not found",
+			request:      `{"channel": "telegram", "enabled": true}`,
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+				m.EXPECT().Set(gomock.Any(), contact, gomock.Any()).Return(errs.ErrNotFound("test-op"))
+			},
+		},
+		{
+			testName:     "failed with unknown channel",
+			request:      `{"channel": "pigeon", "target": "roof", "enabled": true}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+			},
+		},
+		{
+			testName:     "failed with webhook channel without secret",
+			request:      `{"channel": "webhook", "target": "https://hooks.example.com/ifall", "enabled": true}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+			},
+		},
+		{
+			testName:     "failed enabling custom channel without target",
+			request:      `{"channel": "pager", "enabled": true}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockChannelService) {
+				m.EXPECT().Channels().Return(available)
+			},
+		},
+		{
+			testName:     "failed without enabled",
+			request:      `{"channel": "email"}`,
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockChannelService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockChannelService(c)
+			handler := NewChannelsHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Put("/channels", withContacts(contact), handler.SetChannel)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("PUT", "/channels", bytes.NewBufferString(tt.request))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestChannelsHandler_FetchChannels(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	mockService := mock_services.NewMockChannelService(c)
+	mockService.EXPECT().Fetch(gomock.Any(), models.Contacts{Email: "sanya@gmail.com"}).Return([]models.UserChannel{{Channel: models.ChannelEmail, Enabled: true}}, nil)
+	mockService.EXPECT().Channels().Return([]string{models.ChannelEmail, models.ChannelTelegram})
+	handler := NewChannelsHandler(mockService, validator.NewValidator())
+	a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+	a.App.Get("/channels", withContacts(models.Contacts{Email: "sanya@gmail.com"}), handler.FetchChannels)
+	a.App.Get("/anonymous/channels", handler.FetchChannels)
+
+	resp, err := a.App.Test(httptest.NewRequest("GET", "/channels", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = a.App.Test(httptest.NewRequest("GET", "/anonymous/channels", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+}
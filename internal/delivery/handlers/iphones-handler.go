@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"iFall/internal/delivery/apierr"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/services"
+	"iFall/internal/dto"
+	"iFall/pkg/validator"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IPhonesHandler struct {
+	IPhoneService services.IPhoneService
+	Validator     *validator.Validator
+}
+
+func NewIPhonesHandler(is services.IPhoneService, v *validator.Validator) *IPhonesHandler {
+	return &IPhonesHandler{
+		IPhoneService: is,
+		Validator:     v,
+	}
+}
+
+const (
+	defaultIPhonesLimit = 20
+	defaultHistoryRange = 30 * 24 * time.Hour
+	dateLayout          = "2006-01-02"
+	mimeTextCSV         = "text/csv"
+)
+
+func (ih *IPhonesHandler) FetchIPhones(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	req := dto.FetchIPhonesRequest{}
+	if err := c.QueryParser(&req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if err := ih.Validator.Validate.Struct(req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultIPhonesLimit
+	}
+	filter := models.IPhoneFilter{
+		Model:     req.Model,
+		ColorName: req.Color,
+		Esim:      req.Esim,
+		Available: req.Available,
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		SortBy:    req.Sort,
+		Order:     req.Order,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+	iphones, total, err := ih.IPhoneService.Fetch(ctx, filter)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"iphones": iphones,
+		"total":   total,
+		"limit":   req.Limit,
+		"offset":  req.Offset,
+	})
+}
+
+func (ih *IPhonesHandler) GetIPhone(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id := c.Params("id")
+	if id == "" {
+		return apierr.InvalidRequest()
+	}
+	iphone, err := ih.IPhoneService.Get(ctx, id)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(iphone)
+}
+
+func (ih *IPhonesHandler) GetIPhoneHistory(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id := c.Params("id")
+	if id == "" {
+		return apierr.InvalidRequest()
+	}
+	req := dto.IPhoneHistoryRequest{}
+	if err := c.QueryParser(&req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if err := ih.Validator.Validate.Struct(req); err != nil {
+		return apierr.InvalidRequest()
+	}
+	if req.Bucket == "" {
+		req.Bucket = models.BucketDay
+	}
+	to := time.Now().UTC()
+	if req.To != "" {
+		t, isDate, err := parseTimeParam(req.To)
+		if err != nil {
+			return apierr.InvalidRequest()
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+	from := to.Add(-defaultHistoryRange)
+	if req.From != "" {
+		t, _, err := parseTimeParam(req.From)
+		if err != nil {
+			return apierr.InvalidRequest()
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return apierr.InvalidRequest()
+	}
+
+	format := c.Accepts(fiber.MIMEApplicationJSON, mimeTextCSV)
+	if format == "" {
+		return apierr.NotAcceptable()
+	}
+	history, err := ih.IPhoneService.History(ctx, id, from, to, req.Bucket)
+	if err != nil {
+		return apierr.ToApiError(err)
+	}
+	if format == mimeTextCSV {
+		return writeHistoryCSV(c, history)
+	}
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+func (ih *IPhonesHandler) Subscribe(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	id := c.Params("id")
+	if id == "" {
+		return apierr.InvalidRequest()
+	}
+	if err := ih.IPhoneService.Subscribe(ctx, id, contacts); err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (ih *IPhonesHandler) Unsubscribe(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contacts, err := contactsOf(c)
+	if err != nil {
+		return err
+	}
+	id := c.Params("id")
+	if id == "" {
+		return apierr.InvalidRequest()
+	}
+	if err := ih.IPhoneService.Unsubscribe(ctx, id, contacts); err != nil {
+		return apierr.ToApiError(err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func parseTimeParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.UTC(), false, nil
+}
+
+func writeHistoryCSV(c *fiber.Ctx, history *models.PriceHistory) error {
+	c.Set(fiber.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+	if history.AllTime != nil {
+		c.Set("X-All-Time-Min", formatPrice(history.AllTime.Min))
+		c.Set("X-All-Time-Max", formatPrice(history.AllTime.Max))
+	}
+	c.Status(fiber.StatusOK)
+	w := csv.NewWriter(c.Response().BodyWriter())
+	if err := w.Write([]string{"start", "open", "min", "max", "close", "count"}); err != nil {
+		return err
+	}
+	for _, b := range history.Buckets {
+		if err := w.Write([]string{
+			b.Start.Format(time.RFC3339),
+			formatPrice(b.Open),
+			formatPrice(b.Min),
+			formatPrice(b.Max),
+			formatPrice(b.Close),
+			strconv.Itoa(b.Count),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
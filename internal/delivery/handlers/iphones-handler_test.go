@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	mock_services "iFall/internal/domain/services/mocks"
+	"iFall/pkg/errs"
+	"iFall/pkg/server"
+	"iFall/pkg/validator"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPhonesHandler_FetchIPhones(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockIPhoneService)
+	esim := true
+	minPrice := 900.0
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		query        string
+		expectedCode int
+	}{
+		{
+			testName:     "success without filters",
+			query:        "",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Fetch(gomock.Any(), models.IPhoneFilter{Limit: 20}).Return([]models.IPhone{}, 0, nil)
+			},
+		},
+		{
+			testName:     "success with filters",
+			query:        "?model=iPhone%2017&color=green&esim=true&min_price=900&sort=price&order=desc&limit=5&offset=5",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Fetch(gomock.Any(), models.IPhoneFilter{
+					Model:     "iPhone 17",
+					ColorName: "green",
+					Esim:      &esim,
+					MinPrice:  &minPrice,
+					SortBy:    "price",
+					Order:     "desc",
+					Limit:     5,
+					Offset:    5,
+				}).Return([]models.IPhone{{Id: "iphone-green-esim-id"}}, 6, nil)
+			},
+		},
+		{
+			testName:     "failed with invalid sort",
+			query:        "?sort=color",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+		{
+			testName:     "failed with too big limit",
+			query:        "?limit=1000",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+		{
+			testName:     "failed with invalid price",
+			query:        "?max_price=cheap",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockIPhoneService(c)
+			handler := NewIPhonesHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Get("/iphones", handler.FetchIPhones)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("GET", "/iphones"+tt.query, nil)
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestIPhonesHandler_GetIPhone(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockIPhoneService)
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		id           string
+		expectedCode int
+	}{
+		{
+			testName:     "success",
+			id:           "iphone-black-id",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Get(gomock.Any(), "iphone-black-id").Return(&models.IPhone{Id: "iphone-black-id"}, nil)
+			},
+		},
+		{
+			testName:     "not found",
+			id:           "iphone-unknown-id",
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Get(gomock.Any(), "iphone-unknown-id").Return(nil, errs.ErrNotFound("test-op"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockIPhoneService(c)
+			handler := NewIPhonesHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Get("/iphones/:id", handler.GetIPhone)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("GET", "/iphones/"+tt.id, nil)
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestIPhonesHandler_GetIPhoneHistory(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockIPhoneService)
+	from := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)
+	history := &models.PriceHistory{
+		IPhoneId: "iphone-black-id",
+		From:     from,
+		To:       to,
+		Bucket:   models.BucketWeek,
+		Buckets: []models.PriceBucket{
+			{Start: time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), Open: 1000, Min: 900, Max: 1000, Close: 950, Count: 3},
+		},
+		AllTime: &models.PriceExtremes{Min: 900, Max: 1100},
+	}
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		query        string
+		accept       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			testName:     "success json",
+			query:        "?from=2025-11-01&to=2025-11-14&bucket=week",
+			accept:       "application/json",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().History(gomock.Any(), "iphone-black-id", from, to, models.BucketWeek).Return(history, nil)
+			},
+		},
+		{
+			testName:     "success csv",
+			query:        "?from=2025-11-01T00:00:00Z&to=2025-11-15T00:00:00Z&bucket=week",
+			accept:       "text/csv",
+			expectedCode: 200,
+			expectedBody: "start,open,min,max,close,count\n2025-11-10T00:00:00Z,1000.00,900.00,1000.00,950.00,3\n",
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().History(gomock.Any(), "iphone-black-id", from, to, models.BucketWeek).Return(history, nil)
+			},
+		},
+		{
+			testName:     "failed with invalid bucket",
+			query:        "?bucket=month",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+		{
+			testName:     "failed with inverted range",
+			query:        "?from=2025-11-15&to=2025-11-01",
+			expectedCode: 400,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+		{
+			testName:     "failed with unsupported format",
+			query:        "",
+			accept:       "application/xml",
+			expectedCode: 406,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+		{
+			testName:     "not found",
+			query:        "?from=2025-11-01&to=2025-11-14",
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().History(gomock.Any(), "iphone-black-id", from, to, models.BucketDay).Return(nil, errs.ErrNotFound("test-op"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockIPhoneService(c)
+			handler := NewIPhonesHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Get("/iphones/:id/history", handler.GetIPhoneHistory)
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("GET", "/iphones/iphone-black-id/history"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+			if tt.expectedBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, string(body))
+				assert.Equal(t, "900.00", resp.Header.Get("X-All-Time-Min"))
+			}
+		})
+	}
+}
+
+func TestIPhonesHandler_Subscribe(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockIPhoneService)
+	contact := models.Contacts{Email: "sanya@gmail.com"}
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		contacts     *models.Contacts
+		expectedCode int
+	}{
+		{
+			testName:     "success",
+			contacts:     &contact,
+			expectedCode: 200,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Subscribe(gomock.Any(), "iphone-black-id", contact).Return(nil)
+			},
+		},
+		{
+			testName:     "already subscribed",
+			contacts:     &contact,
+			expectedCode: 409,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Subscribe(gomock.Any(), "iphone-black-id", contact).Return(errs.ErrAlreadyExists("test-op", nil))
+			},
+		},
+		{
+			testName:     "user or iphone not found",
+			contacts:     &contact,
+			expectedCode: 404,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {
+				m.EXPECT().Subscribe(gomock.Any(), "iphone-black-id", contact).Return(errs.ErrNotFound("test-op"))
+			},
+		},
+		{
+			testName:     "failed without authentication",
+			contacts:     nil,
+			expectedCode: 401,
+			mockBehavior: func(m *mock_services.MockIPhoneService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockIPhoneService(c)
+			handler := NewIPhonesHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			if tt.contacts != nil {
+				a.App.Post("/iphones/:id/subscriptions", withContacts(*tt.contacts), handler.Subscribe)
+			} else {
+				a.App.Post("/iphones/:id/subscriptions", handler.Subscribe)
+			}
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("POST", "/iphones/iphone-black-id/subscriptions", nil)
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
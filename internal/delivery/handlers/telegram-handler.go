@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"iFall/internal/bot"
+	"iFall/internal/config"
+	"iFall/internal/delivery/apierr"
+
+	"github.com/gofiber/fiber/v2"
+	telebot "gopkg.in/telebot.v4"
+)
+
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type TelegramHandler struct {
+	TelegramBot bot.TelegramBot
+	Config      config.TelegramBotConfig
+}
+
+func NewTelegramHandler(tb bot.TelegramBot, cfg config.TelegramBotConfig) *TelegramHandler {
+	return &TelegramHandler{
+		TelegramBot: tb,
+		Config:      cfg,
+	}
+}
+
+func (th *TelegramHandler) Webhook(c *fiber.Ctx) error {
+	if th.Config.Mode != bot.ModeWebhook {
+		return apierr.NotFound()
+	}
+	secret := c.Get(secretTokenHeader)
+	if th.Config.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(th.Config.WebhookSecret)) != 1 {
+		return apierr.Unauthorized()
+	}
+	update := telebot.Update{}
+	if err := json.Unmarshal(c.Body(), &update); err != nil {
+		return apierr.InvalidJSON()
+	}
+	th.TelegramBot.HandleUpdate(update)
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"iFall/internal/bot"
+	mock_bot "iFall/internal/bot/mocks"
+	"iFall/internal/config"
+	"iFall/pkg/server"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	telebot "gopkg.in/telebot.v4"
+)
+
+func TestTelegramHandler_Webhook(t *testing.T) {
+	type mockBehavior = func(m *mock_bot.MockTelegramBot)
+	webhookConfig := config.TelegramBotConfig{Mode: bot.ModeWebhook, WebhookSecret: "s3cr3t"}
+	start := readPayload(t, "start-message.json")
+	callback := readPayload(t, "price-callback.json")
+	tests := []struct {
+		testName     string
+		mockBehavior mockBehavior
+		config       config.TelegramBotConfig
+		payload      []byte
+		secret       string
+		expectedCode int
+	}{
+		{
+			testName:     "success with command",
+			config:       webhookConfig,
+			payload:      start,
+			secret:       "s3cr3t",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {
+				m.EXPECT().HandleUpdate(gomock.Any()).Do(func(update telebot.Update) {
+					assert.Equal(t, 718204315, update.ID)
+					assert.Equal(t, "/start", update.Message.Text)
+					assert.Equal(t, int64(111), update.Message.Chat.ID)
+				})
+			},
+		},
+		{
+			testName:     "success with callback",
+			config:       webhookConfig,
+			payload:      callback,
+			secret:       "s3cr3t",
+			expectedCode: 200,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {
+				m.EXPECT().HandleUpdate(gomock.Any()).Do(func(update telebot.Update) {
+					assert.Equal(t, "\fchoose_price_yes", update.Callback.Data)
+					assert.Equal(t, "sanya", update.Callback.Sender.Username)
+				})
+			},
+		},
+		{
+			testName:     "failed with wrong secret",
+			config:       webhookConfig,
+			payload:      start,
+			secret:       "guess",
+			expectedCode: 401,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {},
+		},
+		{
+			testName:     "failed without secret",
+			config:       webhookConfig,
+			payload:      start,
+			expectedCode: 401,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {},
+		},
+		{
+			testName:     "not found in poller mode",
+			config:       config.TelegramBotConfig{Mode: bot.ModePoller},
+			payload:      start,
+			expectedCode: 404,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {},
+		},
+		{
+			testName:     "failed with broken payload",
+			config:       webhookConfig,
+			payload:      []byte(`{"update_id": `),
+			secret:       "s3cr3t",
+			expectedCode: 422,
+			mockBehavior: func(m *mock_bot.MockTelegramBot) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			mockBot := mock_bot.NewMockTelegramBot(c)
+			handler := NewTelegramHandler(mockBot, tt.config)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Post("/telegram/webhook", handler.Webhook)
+			tt.mockBehavior(mockBot)
+			req := httptest.NewRequest("POST", "/telegram/webhook", bytes.NewBuffer(tt.payload))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.secret != "" {
+				req.Header.Set(secretTokenHeader, tt.secret)
+			}
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func readPayload(t *testing.T, name string) []byte {
+	payload, err := os.ReadFile(filepath.Join("testdata", "telegram", name))
+	if err != nil {
+		t.Fatalf("failed to read recorded update: %v", err)
+	}
+	return payload
+}
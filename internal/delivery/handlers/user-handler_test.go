@@ -3,13 +3,16 @@ package handlers
 import (
 	"bytes"
 	"iFall/internal/config"
+	"iFall/internal/domain/models"
 	mock_services "iFall/internal/domain/services/mocks"
 	"iFall/internal/utils"
+	"iFall/pkg/errs"
 	"iFall/pkg/server"
 	"iFall/pkg/validator"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -27,7 +30,7 @@ func TestUserHandler_Create(t *testing.T) {
 			request:      `{"name": "sanya", "email": "sanya@gmail.com", "telegram": "tg"}`,
 			expectedCode: 200,
 			mockBehavior: func(m *mock_services.MockUserService) {
-				m.EXPECT().Create(gomock.Any(), "sanya", "sanya@gmail.com", utils.StrToPtr("tg")).Return(nil)
+				m.EXPECT().Create(gomock.Any(), "sanya", "sanya@gmail.com", utils.StrToPtr("tg")).Return(&models.LinkCode{Code: "ABCD2345"}, "token", nil)
 			},
 		},
 		{
@@ -35,7 +38,7 @@ func TestUserHandler_Create(t *testing.T) {
 			request:      `{"name": "sanya", "email": "sanya@gmail.com"}`,
 			expectedCode: 200,
 			mockBehavior: func(m *mock_services.MockUserService) {
-				m.EXPECT().Create(gomock.Any(), "sanya", "sanya@gmail.com", nil).Return(nil)
+				m.EXPECT().Create(gomock.Any(), "sanya", "sanya@gmail.com", nil).Return(&models.LinkCode{Code: "ABCD2345"}, "token", nil)
 			},
 		},
 		{
@@ -76,3 +79,78 @@ func TestUserHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+// withContacts stands in for UsersHandler.Authenticate in tests of the handlers behind it.
+func withContacts(contacts models.Contacts) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(contactsLocal, contacts)
+		return c.Next()
+	}
+}
+
+func TestUserHandler_Authenticate(t *testing.T) {
+	type mockBehavior = func(m *mock_services.MockUserService)
+	user := &models.User{Name: "sanya", Contacts: models.Contacts{Email: "sanya@gmail.com"}}
+	tests := []struct {
+		testName      string
+		mockBehavior  mockBehavior
+		authorization string
+		expectedCode  int
+	}{
+		{
+			testName:      "success",
+			authorization: "Bearer secret",
+			expectedCode:  200,
+			mockBehavior: func(m *mock_services.MockUserService) {
+				m.EXPECT().Authenticate(gomock.Any(), "secret").Return(user, nil)
+			},
+		},
+		{
+			testName:      "unknown token",
+			authorization: "Bearer secret",
+			expectedCode:  401,
+			mockBehavior: func(m *mock_services.MockUserService) {
+				m.EXPECT().Authenticate(gomock.Any(), "secret").Return(nil, errs.ErrNotFound("test-op"))
+			},
+		},
+		{
+			testName:      "failed without header",
+			authorization: "",
+			expectedCode:  401,
+			mockBehavior:  func(m *mock_services.MockUserService) {},
+		},
+		{
+			testName:      "failed with basic auth",
+			authorization: "Basic c2FueWE6c2VjcmV0",
+			expectedCode:  401,
+			mockBehavior:  func(m *mock_services.MockUserService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			validator := validator.NewValidator()
+			mockService := mock_services.NewMockUserService(c)
+			handler := NewUsersHandler(mockService, validator)
+			a := server.NewServer(config.ServerConfig{}, config.AppConfig{})
+			a.App.Get("/me", handler.Authenticate, func(c *fiber.Ctx) error {
+				contacts, err := contactsOf(c)
+				if err != nil {
+					return err
+				}
+				assert.Equal(t, user.Contacts, contacts)
+				return c.SendStatus(fiber.StatusOK)
+			})
+			tt.mockBehavior(mockService)
+			req := httptest.NewRequest("GET", "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := a.App.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"iFall/internal/delivery/apierr"
+	"iFall/internal/domain/models"
 	"iFall/internal/domain/services"
 	"iFall/internal/dto"
+	"iFall/pkg/errs"
 	"iFall/pkg/validator"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -30,10 +34,43 @@ func (uh *UsersHandler) CreateUser(c *fiber.Ctx) error {
 	if err := uh.Validator.Validate.Struct(req); err != nil {
 		return apierr.InvalidRequest()
 	}
-	if err := uh.UserService.Create(ctx, req.Name, req.Email, req.Telegram); err != nil {
+	code, token, err := uh.UserService.Create(ctx, req.Name, req.Email, req.Telegram)
+	if err != nil {
 		return apierr.ToApiError(err)
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "success",
+		"message":    "success",
+		"api_token":  token,
+		"link_code":  code.Code,
+		"link":       code.Link,
+		"expires_at": code.ExpiresAt,
 	})
 }
+
+const contactsLocal = "contacts"
+
+// Authenticate resolves the caller from "Authorization: Bearer <api_token>",
+// handlers behind it read the caller with contactsOf instead of trusting an email from the request.
+func (uh *UsersHandler) Authenticate(c *fiber.Ctx) error {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || token == "" {
+		return apierr.Unauthorized()
+	}
+	user, err := uh.UserService.Authenticate(c.UserContext(), token)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFoundBase) {
+			return apierr.Unauthorized()
+		}
+		return apierr.ToApiError(err)
+	}
+	c.Locals(contactsLocal, user.Contacts)
+	return c.Next()
+}
+
+func contactsOf(c *fiber.Ctx) (models.Contacts, error) {
+	contacts, ok := c.Locals(contactsLocal).(models.Contacts)
+	if !ok {
+		return models.Contacts{}, apierr.Unauthorized()
+	}
+	return contacts, nil
+}
@@ -7,21 +7,56 @@ import (
 )
 
 type RoutesSetup struct {
-	App         *fiber.App
-	UserHandler *handlers.UsersHandler
+	App             *fiber.App
+	UserHandler     *handlers.UsersHandler
+	IPhoneHandler   *handlers.IPhonesHandler
+	AlertHandler    *handlers.AlertsHandler
+	ChannelHandler  *handlers.ChannelsHandler
+	TelegramHandler *handlers.TelegramHandler
 }
 
-func NewRoutesSetup(a *fiber.App, uh *handlers.UsersHandler) *RoutesSetup {
+func NewRoutesSetup(a *fiber.App, uh *handlers.UsersHandler, ih *handlers.IPhonesHandler, ah *handlers.AlertsHandler, ch *handlers.ChannelsHandler, th *handlers.TelegramHandler) *RoutesSetup {
 	return &RoutesSetup{
-		App:         a,
-		UserHandler: uh,
+		App:             a,
+		UserHandler:     uh,
+		IPhoneHandler:   ih,
+		AlertHandler:    ah,
+		ChannelHandler:  ch,
+		TelegramHandler: th,
 	}
 }
 
 func (rs *RoutesSetup) SetupRoutes() {
 	rs.UsersRoutes()
+	rs.IPhonesRoutes()
+	rs.AlertsRoutes()
+	rs.ChannelsRoutes()
+	rs.TelegramRoutes()
 }
 
 func (rs *RoutesSetup) UsersRoutes() {
 	rs.App.Post("/api/v1/users", rs.UserHandler.CreateUser)
 }
+
+func (rs *RoutesSetup) IPhonesRoutes() {
+	rs.App.Get("/api/v1/iphones", rs.IPhoneHandler.FetchIPhones)
+	rs.App.Get("/api/v1/iphones/:id", rs.IPhoneHandler.GetIPhone)
+	rs.App.Get("/api/v1/iphones/:id/history", rs.IPhoneHandler.GetIPhoneHistory)
+	rs.App.Post("/api/v1/iphones/:id/subscriptions", rs.UserHandler.Authenticate, rs.IPhoneHandler.Subscribe)
+	rs.App.Delete("/api/v1/iphones/:id/subscriptions", rs.UserHandler.Authenticate, rs.IPhoneHandler.Unsubscribe)
+}
+
+func (rs *RoutesSetup) AlertsRoutes() {
+	rs.App.Get("/api/v1/alerts", rs.UserHandler.Authenticate, rs.AlertHandler.FetchAlerts)
+	rs.App.Post("/api/v1/alerts", rs.UserHandler.Authenticate, rs.AlertHandler.CreateAlert)
+	rs.App.Delete("/api/v1/alerts/:id", rs.UserHandler.Authenticate, rs.AlertHandler.DeleteAlert)
+}
+
+func (rs *RoutesSetup) ChannelsRoutes() {
+	rs.App.Get("/api/v1/channels", rs.UserHandler.Authenticate, rs.ChannelHandler.FetchChannels)
+	rs.App.Put("/api/v1/channels", rs.UserHandler.Authenticate, rs.ChannelHandler.SetChannel)
+}
+
+func (rs *RoutesSetup) TelegramRoutes() {
+	rs.App.Post("/telegram/webhook", rs.TelegramHandler.Webhook)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AlertKindPrice    = "price"
+	AlertKindDiscount = "discount"
+	AlertKindDrop     = "drop"
+	AlertKindLow      = "low"
+	AlertKindChange   = "change"
+
+	AlertDirectionBelow = "below"
+	AlertDirectionAbove = "above"
+	AlertDirectionAny   = "any"
+
+	AlertRepeatOnce     = "once"
+	AlertRepeatRearm    = "rearm"
+	AlertRepeatCooldown = "cooldown"
+)
+
+type Alert struct {
+	Id            int64      `json:"id"`
+	IPhoneId      *string    `json:"iphone_id"`
+	Model         string     `json:"model"`
+	ColorName     string     `json:"color_name"`
+	Capacity      int        `json:"capacity"`
+	Kind          string     `json:"kind"`
+	Direction     string     `json:"direction"`
+	Threshold     float64    `json:"threshold"`
+	WindowHours   int        `json:"window_hours"`
+	Repeat        string     `json:"repeat"`
+	CooldownHours int        `json:"cooldown_hours"`
+	Fired         bool       `json:"fired"`
+	Paused        bool       `json:"paused"`
+	LastFiredAt   *time.Time `json:"last_fired_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Contacts      `json:"-"`
+}
+
+type FiredAlert struct {
+	Alert  Alert  `json:"alert"`
+	IPhone IPhone `json:"iphone"`
+	Reason string `json:"reason"`
+}
+
+// FormatWindow renders an alert window or cooldown the same way in the bot and in alert reasons.
+func FormatWindow(hours int) string {
+	if hours%24 == 0 {
+		return fmt.Sprintf("%d дн.", hours/24)
+	}
+	return fmt.Sprintf("%d ч.", hours)
+}
@@ -0,0 +1,18 @@
+package models
+
+const (
+	ChannelEmail      = "email"
+	ChannelTelegram   = "telegram"
+	ChannelWebhook    = "webhook"
+	ChannelSlack      = "slack"
+	ChannelDiscord    = "discord"
+	ChannelMattermost = "mattermost"
+)
+
+type UserChannel struct {
+	Channel  string `json:"channel"`
+	Target   string `json:"target"`
+	Enabled  bool   `json:"enabled"`
+	Global   bool   `json:"-"`
+	Contacts `json:"-"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type ChatState struct {
+	ChatId    int64     `json:"chat_id"`
+	State     string    `json:"state"`
+	Data      string    `json:"data"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
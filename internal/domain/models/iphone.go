@@ -1,9 +1,60 @@
 package models
 
+import "time"
+
+const (
+	AvailabilityInStock    = "in_stock"
+	AvailabilityPreorder   = "preorder"
+	AvailabilityOutOfStock = "out_of_stock"
+)
+
 type IPhone struct {
-	Id     string  `json:"id"`
-	Name   string  `json:"name"`
-	Price  float64 `json:"price"`
-	Change float64 `json:"change"`
-	Color  string  `json:"color"`
+	Id           string     `json:"id"`
+	Name         string     `json:"name"`
+	Price        float64    `json:"price"`
+	OldPrice     float64    `json:"old_price"`
+	Discount     float64    `json:"discount"`
+	Installment  float64    `json:"installment"`
+	Change       float64    `json:"change"`
+	Color        string     `json:"color"`
+	Model        string     `json:"model"`
+	Capacity     int        `json:"capacity"`
+	Esim         bool       `json:"esim"`
+	ColorName    string     `json:"color_name"`
+	Active       bool       `json:"active"`
+	CheckedAt    *time.Time `json:"checked_at"`
+	Availability string     `json:"availability"`
+	Offers       []Offer    `json:"offers,omitempty"`
+	BestOffer    *Offer     `json:"best_offer,omitempty"`
+	Spread       float64    `json:"spread"`
+	Source       string     `json:"-"`
+	Currency     string     `json:"-"`
+	Sku          string     `json:"-"`
+	BackInStock  bool       `json:"-"`
+}
+
+type IPhoneFilter struct {
+	Model     string
+	ColorName string
+	Esim      *bool
+	Available *bool
+	MinPrice  *float64
+	MaxPrice  *float64
+	SortBy    string
+	Order     string
+	Limit     int
+	Offset    int
+}
+
+// IPhoneUpdate is the result of one check, Offers and Records are stored along with the iphone.
+type IPhoneUpdate struct {
+	Price        float64
+	OldPrice     float64
+	Discount     float64
+	Installment  float64
+	Availability string
+	// Repriced is false when no offer had a price, the iphone keeps its last change then.
+	Repriced bool
+	Offers   []Offer
+	Records  []PriceRecord
 }
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkCode struct {
+	Code      string    `json:"link_code"`
+	UserId    uuid.UUID `json:"-"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type Offer struct {
+	Id           int64      `json:"id"`
+	IPhoneId     string     `json:"iphone_id"`
+	Source       string     `json:"source"`
+	Url          string     `json:"url"`
+	Price        float64    `json:"price"`
+	OldPrice     float64    `json:"old_price"`
+	Installment  float64    `json:"installment"`
+	Currency     string     `json:"currency"`
+	Sku          string     `json:"sku"`
+	Availability string     `json:"availability"`
+	CheckedAt    *time.Time `json:"checked_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+type OutboxMessage struct {
+	Id          int64      `json:"id"`
+	Channel     string     `json:"channel"`
+	Recipient   string     `json:"recipient"`
+	Subject     string     `json:"subject"`
+	Body        string     `json:"body"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	NextRetryAt time.Time  `json:"next_retry_at"`
+	LastError   *string    `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type PriceRecord struct {
+	Id        int64     `json:"id"`
+	IPhoneId  string    `json:"iphone_id"`
+	Source    string    `json:"source"`
+	Price     float64   `json:"price"`
+	Available bool      `json:"available"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+type PriceBucket struct {
+	Start time.Time `json:"start"`
+	Open  float64   `json:"open"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Close float64   `json:"close"`
+	Count int       `json:"count"`
+}
+
+type PriceExtremes struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type PriceHistory struct {
+	IPhoneId string         `json:"iphone_id"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Bucket   string         `json:"bucket"`
+	Buckets  []PriceBucket  `json:"buckets"`
+	AllTime  *PriceExtremes `json:"all_time"`
+}
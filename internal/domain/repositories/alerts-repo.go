@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=alerts-repo.go -destination=mocks/alerts-repo-mock.go
+type AlertRepository interface {
+	Create(ctx context.Context, alert *models.Alert, contact models.Contacts) error
+	Delete(ctx context.Context, id int64, contact models.Contacts) error
+	FetchByUser(ctx context.Context, contact models.Contacts) ([]models.Alert, error)
+	FetchAll(ctx context.Context) ([]models.Alert, error)
+	UpdateState(ctx context.Context, id int64, fired bool, lastFiredAt *time.Time) error
+	SetPaused(ctx context.Context, id int64, paused bool, contact models.Contacts) error
+	UpdateThreshold(ctx context.Context, id int64, threshold float64, contact models.Contacts) error
+}
+
+type alertRepository struct {
+	Storage *storage.Storage
+}
+
+func NewAlertRepository(s *storage.Storage) AlertRepository {
+	return &alertRepository{
+		Storage: s,
+	}
+}
+
+const alertsRepo = "alertRepository."
+
+const alertColumns = "a.id, a.iphone_id, a.model, a.color_name, a.capacity, a.kind, a.direction, a.threshold, a.window_hours, a.repeat, a.cooldown_hours, a.fired, a.paused, a.last_fired_at, a.created_at, COALESCE(u.email, ''), u.telegram, u.chat_id"
+
+func scanAlert(s scanner, alert *models.Alert) error {
+	return s.Scan(
+		&alert.Id,
+		&alert.IPhoneId,
+		&alert.Model,
+		&alert.ColorName,
+		&alert.Capacity,
+		&alert.Kind,
+		&alert.Direction,
+		&alert.Threshold,
+		&alert.WindowHours,
+		&alert.Repeat,
+		&alert.CooldownHours,
+		&alert.Fired,
+		&alert.Paused,
+		&alert.LastFiredAt,
+		&alert.CreatedAt,
+		&alert.Email,
+		&alert.Telegram,
+		&alert.ChatId,
+	)
+}
+
+func (ar *alertRepository) Create(ctx context.Context, alert *models.Alert, contact models.Contacts) error {
+	op := alertsRepo + "Create"
+	alert.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO alerts (user_id, iphone_id, model, color_name, capacity, kind, direction, threshold, window_hours, repeat, cooldown_hours, created_at)
+		SELECT id, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11 FROM users WHERE chat_id = $12 OR email = $13 RETURNING id`
+	args := []any{alert.IPhoneId, alert.Model, alert.ColorName, alert.Capacity, alert.Kind, alert.Direction, alert.Threshold, alert.WindowHours, alert.Repeat, alert.CooldownHours, alert.CreatedAt, contact.ChatId, contact.Email}
+	if err := ar.Storage.DB.QueryRowContext(ctx, query, args...).Scan(&alert.Id); err != nil {
+		if err == storage.ErrNotFound() {
+			return errs.ErrNotFound(op)
+		}
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
+
+func (ar *alertRepository) Delete(ctx context.Context, id int64, contact models.Contacts) error {
+	op := alertsRepo + "Delete"
+	query := "DELETE FROM alerts WHERE id = $1 AND user_id IN (SELECT id FROM users WHERE chat_id = $2 OR email = $3)"
+	res, err := ar.Storage.DB.ExecContext(ctx, query, id, contact.ChatId, contact.Email)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (ar *alertRepository) FetchByUser(ctx context.Context, contact models.Contacts) ([]models.Alert, error) {
+	op := alertsRepo + "FetchByUser"
+	query := "SELECT " + alertColumns + " FROM alerts a JOIN users u ON u.id = a.user_id WHERE u.chat_id = $1 OR u.email = $2 ORDER BY a.id"
+	alerts, err := ar.fetch(ctx, query, contact.ChatId, contact.Email)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return alerts, nil
+}
+
+func (ar *alertRepository) FetchAll(ctx context.Context) ([]models.Alert, error) {
+	op := alertsRepo + "FetchAll"
+	query := "SELECT " + alertColumns + " FROM alerts a JOIN users u ON u.id = a.user_id ORDER BY a.id"
+	alerts, err := ar.fetch(ctx, query)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return alerts, nil
+}
+
+func (ar *alertRepository) UpdateState(ctx context.Context, id int64, fired bool, lastFiredAt *time.Time) error {
+	op := alertsRepo + "UpdateState"
+	query := "UPDATE alerts SET fired = $1, last_fired_at = $2 WHERE id = $3"
+	res, err := ar.Storage.DB.ExecContext(ctx, query, fired, lastFiredAt, id)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (ar *alertRepository) SetPaused(ctx context.Context, id int64, paused bool, contact models.Contacts) error {
+	op := alertsRepo + "SetPaused"
+	query := "UPDATE alerts SET paused = $1 WHERE id = $2 AND user_id IN (SELECT id FROM users WHERE chat_id = $3 OR email = $4)"
+	res, err := ar.Storage.DB.ExecContext(ctx, query, paused, id, contact.ChatId, contact.Email)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+// UpdateThreshold also rearms the alert, so the new threshold is checked from scratch.
+func (ar *alertRepository) UpdateThreshold(ctx context.Context, id int64, threshold float64, contact models.Contacts) error {
+	op := alertsRepo + "UpdateThreshold"
+	query := "UPDATE alerts SET threshold = $1, fired = 0 WHERE id = $2 AND user_id IN (SELECT id FROM users WHERE chat_id = $3 OR email = $4)"
+	res, err := ar.Storage.DB.ExecContext(ctx, query, threshold, id, contact.ChatId, contact.Email)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (ar *alertRepository) fetch(ctx context.Context, query string, args ...any) ([]models.Alert, error) {
+	alerts := []models.Alert{}
+	res, err := ar.Storage.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var alert models.Alert
+		if err := scanAlert(res, &alert); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareAlertsStorage(t *testing.T) *storage.Storage {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			email TEXT NOT NULL UNIQUE,
+			telegram TEXT UNIQUE,
+			chat_id INTEGER UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			iphone_id TEXT,
+			model TEXT NOT NULL DEFAULT '',
+			color_name TEXT NOT NULL DEFAULT '',
+			capacity INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL DEFAULT 'price',
+			direction TEXT NOT NULL DEFAULT 'below',
+			threshold NUMERIC NOT NULL,
+			window_hours INTEGER NOT NULL DEFAULT 0,
+			repeat TEXT NOT NULL DEFAULT 'rearm',
+			cooldown_hours INTEGER NOT NULL DEFAULT 0,
+			fired BOOLEAN NOT NULL DEFAULT 0,
+			paused BOOLEAN NOT NULL DEFAULT 0,
+			last_fired_at DATETIME,
+			created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test alerts tables: %v", err)
+	}
+	query := "INSERT INTO users (id, name, email, telegram, chat_id) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := storage.DB.Exec(query, "user-1-id", "sanya", "sanya@gmail.com", "tg1", 111); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "user-2-id", "kirill", "kirill@gmail.com", nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	return storage
+}
+
+func TestAlertRepository_Create(t *testing.T) {
+	tests := []struct {
+		testName      string
+		alert         models.Alert
+		contact       models.Contacts
+		expectedError error
+	}{
+		{
+			testName:      "success by chat id",
+			alert:         models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800},
+			contact:       models.Contacts{ChatId: utils.Int64ToPtr(111)},
+			expectedError: nil,
+		},
+		{
+			testName:      "success by email with filter",
+			alert:         models.Alert{Model: "iPhone 17", ColorName: "green", Capacity: 256, Kind: models.AlertKindDiscount, Direction: models.AlertDirectionAbove, Threshold: 10},
+			contact:       models.Contacts{Email: "kirill@gmail.com"},
+			expectedError: nil,
+		},
+		{
+			testName:      "user not found",
+			alert:         models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800},
+			contact:       models.Contacts{Email: "nobody@gmail.com"},
+			expectedError: errs.ErrNotFoundBase,
+		},
+	}
+
+	storage := prepareAlertsStorage(t)
+	repo := NewAlertRepository(storage)
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			alert := tt.alert
+			err := repo.Create(context.Background(), &alert, tt.contact)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.NotZero(t, alert.Id)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAlertRepository_Fetch(t *testing.T) {
+	storage := prepareAlertsStorage(t)
+	repo := NewAlertRepository(storage)
+	iphoneId := "iphone-black-id"
+	first := models.Alert{IPhoneId: &iphoneId, Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800}
+	if err := repo.Create(context.Background(), &first, models.Contacts{ChatId: utils.Int64ToPtr(111)}); err != nil {
+		t.Fatalf("failed to insert test alert: %v", err)
+	}
+	second := models.Alert{ColorName: "green", Kind: models.AlertKindDrop, Direction: models.AlertDirectionBelow, Threshold: 5, WindowHours: 24, Repeat: models.AlertRepeatCooldown, CooldownHours: 12}
+	if err := repo.Create(context.Background(), &second, models.Contacts{Email: "kirill@gmail.com"}); err != nil {
+		t.Fatalf("failed to insert test alert: %v", err)
+	}
+
+	t.Run("success fetching by user", func(t *testing.T) {
+		alerts, err := repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 1)
+		assert.Equal(t, first.Id, alerts[0].Id)
+		assert.Equal(t, &iphoneId, alerts[0].IPhoneId)
+		assert.Equal(t, 2800.0, alerts[0].Threshold)
+		assert.Equal(t, "sanya@gmail.com", alerts[0].Email)
+	})
+
+	t.Run("success fetching all", func(t *testing.T) {
+		alerts, err := repo.FetchAll(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 2)
+		assert.Equal(t, "green", alerts[1].ColorName)
+		assert.Equal(t, 24, alerts[1].WindowHours)
+		assert.Equal(t, models.AlertRepeatCooldown, alerts[1].Repeat)
+		assert.Equal(t, 12, alerts[1].CooldownHours)
+		assert.Nil(t, alerts[1].ChatId)
+	})
+
+	t.Run("success updating state", func(t *testing.T) {
+		firedAt := time.Now().UTC().Truncate(time.Second)
+		assert.NoError(t, repo.UpdateState(context.Background(), first.Id, true, &firedAt))
+		alerts, err := repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.NoError(t, err)
+		assert.True(t, alerts[0].Fired)
+		assert.True(t, firedAt.Equal(*alerts[0].LastFiredAt))
+		assert.ErrorIs(t, repo.UpdateState(context.Background(), 100500, false, nil), errs.ErrNotFoundBase)
+	})
+
+	t.Run("success pausing", func(t *testing.T) {
+		assert.NoError(t, repo.SetPaused(context.Background(), first.Id, true, models.Contacts{ChatId: utils.Int64ToPtr(111)}))
+		alerts, err := repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.NoError(t, err)
+		assert.True(t, alerts[0].Paused)
+		assert.NoError(t, repo.SetPaused(context.Background(), first.Id, false, models.Contacts{ChatId: utils.Int64ToPtr(111)}))
+		alerts, err = repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.NoError(t, err)
+		assert.False(t, alerts[0].Paused)
+	})
+
+	t.Run("not found pausing foreign alert", func(t *testing.T) {
+		err := repo.SetPaused(context.Background(), second.Id, true, models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+
+	t.Run("success updating threshold", func(t *testing.T) {
+		assert.NoError(t, repo.UpdateThreshold(context.Background(), first.Id, 2600, models.Contacts{ChatId: utils.Int64ToPtr(111)}))
+		alerts, err := repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.NoError(t, err)
+		assert.Equal(t, 2600.0, alerts[0].Threshold)
+		assert.False(t, alerts[0].Fired)
+		err = repo.UpdateThreshold(context.Background(), second.Id, 3, models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+
+	t.Run("not found deleting foreign alert", func(t *testing.T) {
+		err := repo.Delete(context.Background(), second.Id, models.Contacts{ChatId: utils.Int64ToPtr(111)})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+
+	t.Run("success deleting", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(context.Background(), second.Id, models.Contacts{Email: "kirill@gmail.com"}))
+		alerts, err := repo.FetchAll(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 1)
+	})
+}
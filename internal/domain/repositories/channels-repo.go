@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=channels-repo.go -destination=mocks/channels-repo-mock.go
+type ChannelRepository interface {
+	Set(ctx context.Context, channel models.UserChannel, contact models.Contacts) error
+	FetchByUser(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error)
+	FetchEnabled(ctx context.Context) ([]models.UserChannel, error)
+}
+
+type channelRepository struct {
+	Storage *storage.Storage
+}
+
+func NewChannelRepository(s *storage.Storage) ChannelRepository {
+	return &channelRepository{
+		Storage: s,
+	}
+}
+
+const channelsRepo = "channelRepository."
+
+// email and telegram are enabled by default for every user who has the contact,
+// user_channels only keeps overrides for them and targets for the other channels.
+const userChannels = `SELECT u.email AS email, u.telegram AS telegram, u.chat_id AS chat_id, 'email' AS channel, '' AS target,
+		COALESCE((SELECT c.enabled FROM user_channels c WHERE c.user_id = u.id AND c.channel = 'email'), 1) AS enabled
+	FROM users u WHERE u.email IS NOT NULL AND u.email <> ''
+	UNION ALL
+	SELECT u.email, u.telegram, u.chat_id, 'telegram', '',
+		COALESCE((SELECT c.enabled FROM user_channels c WHERE c.user_id = u.id AND c.channel = 'telegram'), 1)
+	FROM users u WHERE u.chat_id IS NOT NULL
+	UNION ALL
+	SELECT u.email, u.telegram, u.chat_id, c.channel, c.target, c.enabled
+	FROM user_channels c JOIN users u ON u.id = c.user_id WHERE c.channel NOT IN ('email', 'telegram')`
+
+// users registered through the bot may have no email.
+const userChannelColumns = "SELECT COALESCE(email, ''), telegram, chat_id, channel, target, enabled"
+
+func (cr *channelRepository) Set(ctx context.Context, channel models.UserChannel, contact models.Contacts) error {
+	op := channelsRepo + "Set"
+	query := `INSERT INTO user_channels (user_id, channel, target, enabled, created_at)
+		SELECT id, $1, $2, $3, $4 FROM users WHERE chat_id = $5 OR email = $6
+		ON CONFLICT (user_id, channel) DO UPDATE SET target = excluded.target, enabled = excluded.enabled`
+	res, err := cr.Storage.DB.ExecContext(ctx, query, channel.Channel, channel.Target, channel.Enabled, time.Now().UTC(), contact.ChatId, contact.Email)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (cr *channelRepository) FetchByUser(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error) {
+	op := channelsRepo + "FetchByUser"
+	query := userChannelColumns + " FROM (" + userChannels + ") WHERE chat_id = $1 OR email = $2 ORDER BY channel"
+	channels, err := cr.fetch(ctx, query, contact.ChatId, contact.Email)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return channels, nil
+}
+
+func (cr *channelRepository) FetchEnabled(ctx context.Context) ([]models.UserChannel, error) {
+	op := channelsRepo + "FetchEnabled"
+	query := userChannelColumns + " FROM (" + userChannels + ") WHERE enabled"
+	channels, err := cr.fetch(ctx, query)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return channels, nil
+}
+
+func (cr *channelRepository) fetch(ctx context.Context, query string, args ...any) ([]models.UserChannel, error) {
+	res, err := cr.Storage.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	channels := []models.UserChannel{}
+	for res.Next() {
+		var channel models.UserChannel
+		if err := res.Scan(&channel.Email, &channel.Telegram, &channel.ChatId, &channel.Channel, &channel.Target, &channel.Enabled); err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
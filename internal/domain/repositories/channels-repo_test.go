@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareChannelsStorage(t *testing.T) *storage.Storage {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			email TEXT UNIQUE,
+			telegram TEXT UNIQUE,
+			chat_id INTEGER UNIQUE,
+			telegram_id INTEGER UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS user_channels (
+			user_id TEXT NOT NULL,
+			channel TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, channel)
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test channels tables: %v", err)
+	}
+	query := "INSERT INTO users (id, name, email, telegram, chat_id) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := storage.DB.Exec(query, "user-1-id", "sanya", "sanya@gmail.com", "tg1", 111); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "user-2-id", "kirill", "kirill@gmail.com", nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "user-3-id", "vova", nil, nil, 222); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	return storage
+}
+
+func TestChannelRepository(t *testing.T) {
+	storage := prepareChannelsStorage(t)
+	repo := NewChannelRepository(storage)
+	sanya := models.Contacts{ChatId: utils.Int64ToPtr(111)}
+
+	t.Run("success default channels", func(t *testing.T) {
+		channels, err := repo.FetchEnabled(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, channels, 4)
+		channels, err = repo.FetchByUser(context.Background(), sanya)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{models.ChannelEmail, models.ChannelTelegram}, []string{channels[0].Channel, channels[1].Channel})
+		assert.True(t, channels[0].Enabled)
+		assert.Equal(t, int64(111), *channels[1].ChatId)
+	})
+
+	t.Run("success disabling and adding channels", func(t *testing.T) {
+		assert.NoError(t, repo.Set(context.Background(), models.UserChannel{Channel: models.ChannelEmail}, sanya))
+		assert.NoError(t, repo.Set(context.Background(), models.UserChannel{Channel: "webhook", Target: "https://example.com/hook", Enabled: true}, models.Contacts{Email: "kirill@gmail.com"}))
+		channels, err := repo.FetchEnabled(context.Background())
+		assert.NoError(t, err)
+		enabled := []string{}
+		for _, c := range channels {
+			enabled = append(enabled, c.Email+":"+c.Channel+":"+c.Target)
+		}
+		assert.ElementsMatch(t, []string{"sanya@gmail.com:telegram:", ":telegram:", "kirill@gmail.com:email:", "kirill@gmail.com:webhook:https://example.com/hook"}, enabled)
+		channels, err = repo.FetchByUser(context.Background(), sanya)
+		assert.NoError(t, err)
+		assert.False(t, channels[0].Enabled)
+	})
+
+	t.Run("success enabling again", func(t *testing.T) {
+		assert.NoError(t, repo.Set(context.Background(), models.UserChannel{Channel: models.ChannelEmail, Enabled: true}, sanya))
+		channels, err := repo.FetchByUser(context.Background(), sanya)
+		assert.NoError(t, err)
+		assert.True(t, channels[0].Enabled)
+	})
+
+	t.Run("success user without email", func(t *testing.T) {
+		channels, err := repo.FetchByUser(context.Background(), models.Contacts{ChatId: utils.Int64ToPtr(222)})
+		assert.NoError(t, err)
+		assert.Len(t, channels, 1)
+		assert.Equal(t, models.ChannelTelegram, channels[0].Channel)
+		assert.Equal(t, "", channels[0].Email)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		err := repo.Set(context.Background(), models.UserChannel{Channel: models.ChannelEmail}, models.Contacts{Email: "nobody@gmail.com"})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+}
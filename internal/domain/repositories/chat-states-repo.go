@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=chat-states-repo.go -destination=mocks/chat-states-repo-mock.go
+type ChatStateRepository interface {
+	Get(ctx context.Context, chatId int64) (*models.ChatState, error)
+	Set(ctx context.Context, state models.ChatState) error
+	Delete(ctx context.Context, chatId int64) error
+}
+
+type chatStateRepository struct {
+	Storage *storage.Storage
+}
+
+func NewChatStateRepository(s *storage.Storage) ChatStateRepository {
+	return &chatStateRepository{
+		Storage: s,
+	}
+}
+
+const chatStatesRepo = "chatStateRepository."
+
+func (csr *chatStateRepository) Get(ctx context.Context, chatId int64) (*models.ChatState, error) {
+	op := chatStatesRepo + "Get"
+	query := "SELECT chat_id, state, data, expires_at, updated_at FROM chat_states WHERE chat_id = $1 AND expires_at > $2"
+	var state models.ChatState
+	if err := csr.Storage.DB.QueryRowContext(ctx, query, chatId, time.Now().UTC()).Scan(
+		&state.ChatId,
+		&state.State,
+		&state.Data,
+		&state.ExpiresAt,
+		&state.UpdatedAt,
+	); err != nil {
+		if err == storage.ErrNotFound() {
+			return nil, errs.ErrNotFound(op)
+		}
+		return nil, errs.NewAppError(op, err)
+	}
+	return &state, nil
+}
+
+func (csr *chatStateRepository) Set(ctx context.Context, state models.ChatState) error {
+	op := chatStatesRepo + "Set"
+	query := `INSERT INTO chat_states (chat_id, state, data, expires_at, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_id) DO UPDATE SET state = excluded.state, data = excluded.data, expires_at = excluded.expires_at, updated_at = excluded.updated_at`
+	if _, err := csr.Storage.DB.ExecContext(ctx, query, state.ChatId, state.State, state.Data, state.ExpiresAt.UTC(), time.Now().UTC()); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
+
+func (csr *chatStateRepository) Delete(ctx context.Context, chatId int64) error {
+	op := chatStatesRepo + "Delete"
+	if _, err := csr.Storage.DB.ExecContext(ctx, "DELETE FROM chat_states WHERE chat_id = $1", chatId); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
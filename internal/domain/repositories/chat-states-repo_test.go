@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareChatStatesStorage(t *testing.T) *storage.Storage {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS chat_states (
+			chat_id INTEGER PRIMARY KEY,
+			state TEXT NOT NULL,
+			data TEXT NOT NULL DEFAULT '',
+			expires_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test chat states table: %v", err)
+	}
+	return storage
+}
+
+func TestChatStateRepository(t *testing.T) {
+	storage := prepareChatStatesStorage(t)
+	repo := NewChatStateRepository(storage)
+
+	t.Run("not found without state", func(t *testing.T) {
+		_, err := repo.Get(context.Background(), 111)
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+
+	t.Run("success setting and overwriting", func(t *testing.T) {
+		assert.NoError(t, repo.Set(context.Background(), models.ChatState{ChatId: 111, State: "asking_for_price", ExpiresAt: time.Now().Add(time.Hour)}))
+		assert.NoError(t, repo.Set(context.Background(), models.ChatState{ChatId: 111, State: "choosing_price", Data: "2800", ExpiresAt: time.Now().Add(time.Hour)}))
+		state, err := repo.Get(context.Background(), 111)
+		assert.NoError(t, err)
+		assert.Equal(t, "choosing_price", state.State)
+		assert.Equal(t, "2800", state.Data)
+	})
+
+	t.Run("not found with expired state", func(t *testing.T) {
+		assert.NoError(t, repo.Set(context.Background(), models.ChatState{ChatId: 222, State: "choosing_price", ExpiresAt: time.Now().Add(-time.Minute)}))
+		_, err := repo.Get(context.Background(), 222)
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+
+	t.Run("success deleting", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(context.Background(), 111))
+		_, err := repo.Get(context.Background(), 111)
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+}
@@ -3,15 +3,20 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"iFall/internal/domain/models"
 	"iFall/pkg/errs"
 	"iFall/pkg/storage"
+	"strings"
+	"time"
 )
 
 //go:generate mockgen -source=iphones-repo.go -destination=mocks/iphones-repo-mock.go
 type IPhoneRepository interface {
 	Get(ctx context.Context, id string) (*models.IPhone, error)
-	Update(ctx context.Context, id string, price float64) (*models.IPhone, error)
+	FetchActive(ctx context.Context) ([]models.IPhone, error)
+	Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error)
+	Update(ctx context.Context, id string, update models.IPhoneUpdate) (*models.IPhone, error)
 }
 
 type iPhoneRepository struct {
@@ -26,39 +31,175 @@ func NewIPhoneRepository(s *storage.Storage) IPhoneRepository {
 
 const iphonesRepo = "iPhoneRepository."
 
-func (ir *iPhoneRepository) Get(ctx context.Context, id string) (*models.IPhone, error) {
-	op := iphonesRepo + "Get"
-	query := "SELECT * FROM iphones WHERE id = $1"
-	iphone := &models.IPhone{}
-	if err := ir.Storage.DB.QueryRowContext(ctx, query, id).Scan(
+const iphoneColumns = "id, name, price, change, color, model, capacity, esim, color_name, active, checked_at, availability, old_price, discount, installment"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIPhone(s scanner, iphone *models.IPhone) error {
+	return s.Scan(
 		&iphone.Id,
 		&iphone.Name,
 		&iphone.Price,
 		&iphone.Change,
 		&iphone.Color,
-	); err != nil {
+		&iphone.Model,
+		&iphone.Capacity,
+		&iphone.Esim,
+		&iphone.ColorName,
+		&iphone.Active,
+		&iphone.CheckedAt,
+		&iphone.Availability,
+		&iphone.OldPrice,
+		&iphone.Discount,
+		&iphone.Installment,
+	)
+}
+
+func (ir *iPhoneRepository) Get(ctx context.Context, id string) (*models.IPhone, error) {
+	op := iphonesRepo + "Get"
+	query := "SELECT " + iphoneColumns + " FROM iphones WHERE id = $1"
+	iphone := &models.IPhone{}
+	if err := scanIPhone(ir.Storage.DB.QueryRowContext(ctx, query, id), iphone); err != nil {
 		if errors.Is(err, storage.ErrNotFound()) {
 			return nil, errs.ErrNotFound(op)
 		}
+		return nil, errs.NewAppError(op, err)
 	}
 	return iphone, nil
 }
 
-func (ir *iPhoneRepository) Update(ctx context.Context, id string, price float64) (*models.IPhone, error) {
+func (ir *iPhoneRepository) FetchActive(ctx context.Context) ([]models.IPhone, error) {
+	op := iphonesRepo + "FetchActive"
+	query := "SELECT " + iphoneColumns + " FROM iphones WHERE active = 1 ORDER BY id"
+	iphones := []models.IPhone{}
+	res, err := ir.Storage.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var iphone models.IPhone
+		if err := scanIPhone(res, &iphone); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		iphones = append(iphones, iphone)
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return iphones, nil
+}
+
+var iphonesSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "price",
+	"change":     "change",
+	"checked_at": "checked_at",
+}
+
+func (ir *iPhoneRepository) Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error) {
+	op := iphonesRepo + "Fetch"
+	conds := []string{"active = 1"}
+	args := []any{}
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", fmt.Sprintf("$%d", len(args))))
+	}
+	if filter.Model != "" {
+		addCond("model = ? COLLATE NOCASE", filter.Model)
+	}
+	if filter.ColorName != "" {
+		addCond("color_name = ? COLLATE NOCASE", filter.ColorName)
+	}
+	if filter.Esim != nil {
+		addCond("esim = ?", *filter.Esim)
+	}
+	if filter.Available != nil {
+		if *filter.Available {
+			addCond("availability = ?", models.AvailabilityInStock)
+		} else {
+			addCond("availability != ?", models.AvailabilityInStock)
+		}
+	}
+	if filter.MinPrice != nil {
+		addCond("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCond("price <= ?", *filter.MaxPrice)
+	}
+	where := " WHERE " + strings.Join(conds, " AND ")
+
+	var total int
+	if err := ir.Storage.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM iphones"+where, args...).Scan(&total); err != nil {
+		return nil, 0, errs.NewAppError(op, err)
+	}
+
+	sortBy, ok := iphonesSortColumns[filter.SortBy]
+	if !ok {
+		sortBy = "name"
+	}
+	order := "ASC"
+	if strings.EqualFold(filter.Order, "desc") {
+		order = "DESC"
+	}
+	query := fmt.Sprintf("SELECT %s FROM iphones%s ORDER BY %s %s, id LIMIT $%d OFFSET $%d", iphoneColumns, where, sortBy, order, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	iphones := []models.IPhone{}
+	res, err := ir.Storage.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var iphone models.IPhone
+		if err := scanIPhone(res, &iphone); err != nil {
+			return nil, 0, errs.NewAppError(op, err)
+		}
+		iphones = append(iphones, iphone)
+	}
+	if err := res.Err(); err != nil {
+		return nil, 0, errs.NewAppError(op, err)
+	}
+	return iphones, total, nil
+}
+
+func (ir *iPhoneRepository) Update(ctx context.Context, id string, update models.IPhoneUpdate) (*models.IPhone, error) {
 	op := iphonesRepo + "Update"
-	query := "UPDATE iphones SET price=$1, change=$1-iphones.price WHERE id=$2 RETURNING name, price, color, change"
+	tx, err := ir.Storage.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer tx.Rollback()
+	query := `UPDATE iphones SET price=$1, change=CASE WHEN $8 THEN $1-iphones.price ELSE iphones.change END, checked_at=$3,
+		availability=$4, old_price=$5, discount=$6, installment=$7 WHERE id=$2 RETURNING ` + iphoneColumns
 	iphone := &models.IPhone{}
-	if err := ir.Storage.DB.QueryRowContext(ctx, query, price, id).Scan(
-		&iphone.Name,
-		&iphone.Price,
-		&iphone.Color,
-		&iphone.Change,
-	); err != nil {
+	args := []any{update.Price, id, time.Now().UTC(), update.Availability, update.OldPrice, update.Discount, update.Installment, update.Repriced}
+	if err := scanIPhone(tx.QueryRowContext(ctx, query, args...), iphone); err != nil {
 		if errors.Is(err, storage.ErrNotFound()) {
 			return nil, errs.ErrNotFound(op)
 		}
 		return nil, errs.NewAppError(op, err)
 	}
+	for _, offer := range update.Offers {
+		res, err := tx.ExecContext(ctx, updateOfferQuery, offerUpdateArgs(offer)...)
+		if err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		if nr, _ := res.RowsAffected(); nr == 0 {
+			return nil, errs.ErrNotFound(op)
+		}
+	}
+	for _, record := range update.Records {
+		if _, err := tx.ExecContext(ctx, createPriceRecordQuery, priceRecordArgs(&record)...); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
 
 	return iphone, nil
 }
@@ -39,11 +39,13 @@ func TestIPhoneRepository_Get(t *testing.T) {
 				color:  "ffffff",
 			},
 			expectedResult: &models.IPhone{
-				Id:     "iphone-1-id",
-				Name:   "iphone1",
-				Price:  1000.0,
-				Change: 0.0,
-				Color:  "ffffff",
+				Id:           "iphone-1-id",
+				Name:         "iphone1",
+				Price:        1000.0,
+				Change:       0.0,
+				Color:        "ffffff",
+				Active:       true,
+				Availability: models.AvailabilityInStock,
 			},
 		},
 		{
@@ -62,7 +64,17 @@ func TestIPhoneRepository_Get(t *testing.T) {
     				name TEXT NOT NULL UNIQUE,
     				price NUMERIC NOT NULL,
     				change NUMERIC NOT NULL DEFAULT 0,
-    				color TEXT NOT NULL DEFAULT 'ffffff'
+    				color TEXT NOT NULL DEFAULT 'ffffff',
+    				model TEXT NOT NULL DEFAULT '',
+    				capacity INTEGER NOT NULL DEFAULT 0,
+    				esim BOOLEAN NOT NULL DEFAULT 0,
+    				color_name TEXT NOT NULL DEFAULT '',
+    				active BOOLEAN NOT NULL DEFAULT 1,
+    				checked_at DATETIME,
+    				availability TEXT NOT NULL DEFAULT 'in_stock',
+    				old_price NUMERIC NOT NULL DEFAULT 0,
+    				discount NUMERIC NOT NULL DEFAULT 0,
+    				installment NUMERIC NOT NULL DEFAULT 0
 				);				
     		`
 	if _, err := storage.DB.Exec(schema); err != nil {
@@ -95,26 +107,63 @@ func TestIPhoneRepository_Update(t *testing.T) {
 	tests := []struct {
 		testName       string
 		id             string
-		price          float64
+		update         models.IPhoneUpdate
 		expectedResult *models.IPhone
 		expectedError  error
 	}{
 		{
 			testName: "success updating",
 			id:       "test-iphone-id",
-			price:    800,
+			update: models.IPhoneUpdate{
+				Price:        800,
+				OldPrice:     900,
+				Discount:     11.1,
+				Installment:  70,
+				Availability: models.AvailabilityPreorder,
+				Repriced:     true,
+			},
 			expectedResult: &models.IPhone{
-				Name:   "iphone-name",
-				Price:  800,
-				Color:  "ffffff",
-				Change: -100,
+				Id:           "test-iphone-id",
+				Name:         "iphone-name",
+				Price:        800,
+				OldPrice:     900,
+				Discount:     11.1,
+				Installment:  70,
+				Color:        "ffffff",
+				Change:       -100,
+				Active:       true,
+				Availability: models.AvailabilityPreorder,
+			},
+			expectedError: nil,
+		},
+		{
+			testName: "success keeping change without price",
+			id:       "test-iphone-id",
+			update: models.IPhoneUpdate{
+				Price:        800,
+				OldPrice:     900,
+				Discount:     11.1,
+				Installment:  70,
+				Availability: models.AvailabilityOutOfStock,
+			},
+			expectedResult: &models.IPhone{
+				Id:           "test-iphone-id",
+				Name:         "iphone-name",
+				Price:        800,
+				OldPrice:     900,
+				Discount:     11.1,
+				Installment:  70,
+				Color:        "ffffff",
+				Change:       -100,
+				Active:       true,
+				Availability: models.AvailabilityOutOfStock,
 			},
 			expectedError: nil,
 		},
 		{
 			testName: "not found",
 			id:       "test-iphone-id2",
-			price:    800,
+			update:   models.IPhoneUpdate{Price: 800, Availability: models.AvailabilityInStock},
 			expectedResult: &models.IPhone{
 				Name:   "iphone-name",
 				Price:  800,
@@ -133,7 +182,17 @@ func TestIPhoneRepository_Update(t *testing.T) {
     				name TEXT NOT NULL UNIQUE,
     				price NUMERIC NOT NULL,
     				change NUMERIC NOT NULL DEFAULT 0,
-    				color TEXT NOT NULL DEFAULT 'ffffff'
+    				color TEXT NOT NULL DEFAULT 'ffffff',
+    				model TEXT NOT NULL DEFAULT '',
+    				capacity INTEGER NOT NULL DEFAULT 0,
+    				esim BOOLEAN NOT NULL DEFAULT 0,
+    				color_name TEXT NOT NULL DEFAULT '',
+    				active BOOLEAN NOT NULL DEFAULT 1,
+    				checked_at DATETIME,
+    				availability TEXT NOT NULL DEFAULT 'in_stock',
+    				old_price NUMERIC NOT NULL DEFAULT 0,
+    				discount NUMERIC NOT NULL DEFAULT 0,
+    				installment NUMERIC NOT NULL DEFAULT 0
 				);				
     		`
 	if _, err := storage.DB.Exec(schema); err != nil {
@@ -147,9 +206,11 @@ func TestIPhoneRepository_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			repo := NewIPhoneRepository(storage)
-			iphone, err := repo.Update(context.Background(), tt.id, tt.price)
+			iphone, err := repo.Update(context.Background(), tt.id, tt.update)
 			if tt.expectedError == nil {
 				assert.NoError(t, err)
+				assert.NotNil(t, iphone.CheckedAt)
+				iphone.CheckedAt = nil
 				assert.Equal(t, tt.expectedResult, iphone)
 			} else {
 				assert.Error(t, err)
@@ -158,3 +219,307 @@ func TestIPhoneRepository_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestIPhoneRepository_UpdateWithOffers(t *testing.T) {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS iphones (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			price NUMERIC NOT NULL,
+			change NUMERIC NOT NULL DEFAULT 0,
+			color TEXT NOT NULL DEFAULT 'ffffff',
+			model TEXT NOT NULL DEFAULT '',
+			capacity INTEGER NOT NULL DEFAULT 0,
+			esim BOOLEAN NOT NULL DEFAULT 0,
+			color_name TEXT NOT NULL DEFAULT '',
+			active BOOLEAN NOT NULL DEFAULT 1,
+			checked_at DATETIME,
+			availability TEXT NOT NULL DEFAULT 'in_stock',
+			old_price NUMERIC NOT NULL DEFAULT 0,
+			discount NUMERIC NOT NULL DEFAULT 0,
+			installment NUMERIC NOT NULL DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS offers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			url TEXT NOT NULL UNIQUE,
+			price NUMERIC NOT NULL DEFAULT 0,
+			old_price NUMERIC NOT NULL DEFAULT 0,
+			installment NUMERIC NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT '',
+			sku TEXT NOT NULL DEFAULT '',
+			availability TEXT NOT NULL DEFAULT 'in_stock',
+			checked_at DATETIME
+		);
+		CREATE TABLE IF NOT EXISTS price_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			price NUMERIC NOT NULL,
+			available BOOLEAN NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	if _, err := storage.DB.Exec("INSERT INTO iphones (id, name, price) VALUES($1, $2, $3)", "test-iphone-id", "iphone-name", 900); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec("INSERT INTO offers (iphone_id, source, url) VALUES($1, $2, $3)", "test-iphone-id", "newton.by", "https://newton.by/iphone"); err != nil {
+		t.Fatalf("failed to insert test offer data: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewIPhoneRepository(storage)
+	now := time.Now()
+	countRecords := func() int {
+		var n int
+		if err := storage.DB.QueryRow("SELECT COUNT(*) FROM price_history").Scan(&n); err != nil {
+			t.Fatalf("failed to count price records: %v", err)
+		}
+		return n
+	}
+
+	t.Run("rolls back when an offer is missing", func(t *testing.T) {
+		_, err := repo.Update(ctx, "test-iphone-id", models.IPhoneUpdate{
+			Price:        800,
+			Availability: models.AvailabilityInStock,
+			Repriced:     true,
+			Offers:       []models.Offer{{Id: 42, Price: 800, CheckedAt: &now}},
+			Records:      []models.PriceRecord{{IPhoneId: "test-iphone-id", Source: "newton.by", Price: 800, Available: true, CreatedAt: now}},
+		})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+		iphone, err := repo.Get(ctx, "test-iphone-id")
+		assert.NoError(t, err)
+		assert.Equal(t, 900.0, iphone.Price)
+		assert.Nil(t, iphone.CheckedAt)
+		assert.Equal(t, 0, countRecords())
+	})
+
+	t.Run("success storing offers and records", func(t *testing.T) {
+		iphone, err := repo.Update(ctx, "test-iphone-id", models.IPhoneUpdate{
+			Price:        800,
+			Availability: models.AvailabilityInStock,
+			Repriced:     true,
+			Offers:       []models.Offer{{Id: 1, Price: 800, Availability: models.AvailabilityInStock, CheckedAt: &now}},
+			Records:      []models.PriceRecord{{IPhoneId: "test-iphone-id", Source: "newton.by", Price: 800, Available: true, CreatedAt: now}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 800.0, iphone.Price)
+		var price float64
+		if err := storage.DB.QueryRow("SELECT price FROM offers WHERE id = 1").Scan(&price); err != nil {
+			t.Fatalf("failed to fetch offer price: %v", err)
+		}
+		assert.Equal(t, 800.0, price)
+		assert.Equal(t, 1, countRecords())
+	})
+}
+
+func TestIPhoneRepository_FetchActive(t *testing.T) {
+	tests := []struct {
+		testName       string
+		expectedResult []models.IPhone
+		expectedError  error
+	}{
+		{
+			testName: "success fetching",
+			expectedResult: []models.IPhone{
+				{
+					Id:           "iphone-black-id",
+					Name:         "iphone-black",
+					Price:        900,
+					Color:        "353839",
+					Model:        "iPhone 17",
+					Capacity:     256,
+					Esim:         false,
+					ColorName:    "black",
+					Active:       true,
+					Availability: models.AvailabilityInStock,
+				},
+				{
+					Id:           "iphone-green-esim-id",
+					Name:         "iphone-green-esim",
+					Price:        1000,
+					Color:        "A9B689",
+					Model:        "iPhone 17",
+					Capacity:     512,
+					Esim:         true,
+					ColorName:    "green",
+					Active:       true,
+					Availability: models.AvailabilityInStock,
+				},
+			},
+			expectedError: nil,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+
+	schema := `
+   				CREATE TABLE IF NOT EXISTS iphones (
+    				id TEXT PRIMARY KEY,
+    				name TEXT NOT NULL UNIQUE,
+    				price NUMERIC NOT NULL,
+    				change NUMERIC NOT NULL DEFAULT 0,
+    				color TEXT NOT NULL DEFAULT 'ffffff',
+    				model TEXT NOT NULL DEFAULT '',
+    				capacity INTEGER NOT NULL DEFAULT 0,
+    				esim BOOLEAN NOT NULL DEFAULT 0,
+    				color_name TEXT NOT NULL DEFAULT '',
+    				active BOOLEAN NOT NULL DEFAULT 1,
+    				checked_at DATETIME,
+    				availability TEXT NOT NULL DEFAULT 'in_stock',
+    				old_price NUMERIC NOT NULL DEFAULT 0,
+    				discount NUMERIC NOT NULL DEFAULT 0,
+    				installment NUMERIC NOT NULL DEFAULT 0
+				);
+    		`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test iphones table: %v", err)
+	}
+
+	query := "INSERT INTO iphones (id, name, price, color, model, capacity, esim, color_name, active) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+	if _, err := storage.DB.Exec(query, "iphone-black-id", "iphone-black", 900, "353839", "iPhone 17", 256, false, "black", true); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-green-esim-id", "iphone-green-esim", 1000, "A9B689", "iPhone 17", 512, true, "green", true); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-retired-id", "iphone-retired", 800, "ffffff", "iPhone 16", 128, false, "white", false); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := NewIPhoneRepository(storage)
+			iphones, err := repo.FetchActive(context.Background())
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, iphones)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestIPhoneRepository_Fetch(t *testing.T) {
+	esim := true
+	unavailable := false
+	minPrice := 950.0
+	tests := []struct {
+		testName      string
+		filter        models.IPhoneFilter
+		expectedIds   []string
+		expectedTotal int
+		expectedError error
+	}{
+		{
+			testName:      "success fetching all",
+			filter:        models.IPhoneFilter{Limit: 10},
+			expectedIds:   []string{"iphone-black-id", "iphone-green-esim-id", "iphone-green-id"},
+			expectedTotal: 3,
+			expectedError: nil,
+		},
+		{
+			testName:      "success filtering by color",
+			filter:        models.IPhoneFilter{ColorName: "GREEN", Limit: 10},
+			expectedIds:   []string{"iphone-green-esim-id", "iphone-green-id"},
+			expectedTotal: 2,
+			expectedError: nil,
+		},
+		{
+			testName:      "success filtering by esim and price",
+			filter:        models.IPhoneFilter{Esim: &esim, MinPrice: &minPrice, Limit: 10},
+			expectedIds:   []string{"iphone-green-esim-id"},
+			expectedTotal: 1,
+			expectedError: nil,
+		},
+		{
+			testName:      "success sorting by price desc with pagination",
+			filter:        models.IPhoneFilter{SortBy: "price", Order: "desc", Limit: 2, Offset: 1},
+			expectedIds:   []string{"iphone-green-id", "iphone-black-id"},
+			expectedTotal: 3,
+			expectedError: nil,
+		},
+		{
+			testName:      "success filtering unavailable",
+			filter:        models.IPhoneFilter{Available: &unavailable, Limit: 10},
+			expectedIds:   []string{"iphone-green-id"},
+			expectedTotal: 1,
+			expectedError: nil,
+		},
+		{
+			testName:      "success empty result",
+			filter:        models.IPhoneFilter{Model: "iPhone 16", Limit: 10},
+			expectedIds:   []string{},
+			expectedTotal: 0,
+			expectedError: nil,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+
+	schema := `
+   				CREATE TABLE IF NOT EXISTS iphones (
+    				id TEXT PRIMARY KEY,
+    				name TEXT NOT NULL UNIQUE,
+    				price NUMERIC NOT NULL,
+    				change NUMERIC NOT NULL DEFAULT 0,
+    				color TEXT NOT NULL DEFAULT 'ffffff',
+    				model TEXT NOT NULL DEFAULT '',
+    				capacity INTEGER NOT NULL DEFAULT 0,
+    				esim BOOLEAN NOT NULL DEFAULT 0,
+    				color_name TEXT NOT NULL DEFAULT '',
+    				active BOOLEAN NOT NULL DEFAULT 1,
+    				checked_at DATETIME,
+    				availability TEXT NOT NULL DEFAULT 'in_stock',
+    				old_price NUMERIC NOT NULL DEFAULT 0,
+    				discount NUMERIC NOT NULL DEFAULT 0,
+    				installment NUMERIC NOT NULL DEFAULT 0
+				);
+    		`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test iphones table: %v", err)
+	}
+
+	query := "INSERT INTO iphones (id, name, price, model, esim, color_name, active) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	if _, err := storage.DB.Exec(query, "iphone-black-id", "a-iphone-black", 900, "iPhone 17", false, "black", true); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-green-esim-id", "b-iphone-green-esim", 1000, "iPhone 17", true, "green", true); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-green-id", "c-iphone-green", 950, "iPhone 17", false, "green", true); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-retired-id", "d-iphone-retired", 800, "iPhone 16", false, "white", false); err != nil {
+		t.Fatalf("failed to insert test iphone data: %v", err)
+	}
+	if _, err := storage.DB.Exec("UPDATE iphones SET availability = $1 WHERE id = $2", models.AvailabilityOutOfStock, "iphone-green-id"); err != nil {
+		t.Fatalf("failed to update test iphone data: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := NewIPhoneRepository(storage)
+			iphones, total, err := repo.Fetch(context.Background(), tt.filter)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				ids := []string{}
+				for _, iphone := range iphones {
+					ids = append(ids, iphone.Id)
+				}
+				assert.Equal(t, tt.expectedIds, ids)
+				assert.Equal(t, tt.expectedTotal, total)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: alerts-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAlertRepository is a mock of AlertRepository interface.
+type MockAlertRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRepositoryMockRecorder
+}
+
+// MockAlertRepositoryMockRecorder is the mock recorder for MockAlertRepository.
+type MockAlertRepositoryMockRecorder struct {
+	mock *MockAlertRepository
+}
+
+// NewMockAlertRepository creates a new mock instance.
+func NewMockAlertRepository(ctrl *gomock.Controller) *MockAlertRepository {
+	mock := &MockAlertRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRepository) EXPECT() *MockAlertRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAlertRepository) Create(ctx context.Context, alert *models.Alert, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, alert, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAlertRepositoryMockRecorder) Create(ctx, alert, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAlertRepository)(nil).Create), ctx, alert, contact)
+}
+
+// Delete mocks base method.
+func (m *MockAlertRepository) Delete(ctx context.Context, id int64, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertRepositoryMockRecorder) Delete(ctx, id, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertRepository)(nil).Delete), ctx, id, contact)
+}
+
+// FetchAll mocks base method.
+func (m *MockAlertRepository) FetchAll(ctx context.Context) ([]models.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAll", ctx)
+	ret0, _ := ret[0].([]models.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAll indicates an expected call of FetchAll.
+func (mr *MockAlertRepositoryMockRecorder) FetchAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAll", reflect.TypeOf((*MockAlertRepository)(nil).FetchAll), ctx)
+}
+
+// FetchByUser mocks base method.
+func (m *MockAlertRepository) FetchByUser(ctx context.Context, contact models.Contacts) ([]models.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByUser", ctx, contact)
+	ret0, _ := ret[0].([]models.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByUser indicates an expected call of FetchByUser.
+func (mr *MockAlertRepositoryMockRecorder) FetchByUser(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByUser", reflect.TypeOf((*MockAlertRepository)(nil).FetchByUser), ctx, contact)
+}
+
+// SetPaused mocks base method.
+func (m *MockAlertRepository) SetPaused(ctx context.Context, id int64, paused bool, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPaused", ctx, id, paused, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPaused indicates an expected call of SetPaused.
+func (mr *MockAlertRepositoryMockRecorder) SetPaused(ctx, id, paused, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaused", reflect.TypeOf((*MockAlertRepository)(nil).SetPaused), ctx, id, paused, contact)
+}
+
+// UpdateState mocks base method.
+func (m *MockAlertRepository) UpdateState(ctx context.Context, id int64, fired bool, lastFiredAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateState", ctx, id, fired, lastFiredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateState indicates an expected call of UpdateState.
+func (mr *MockAlertRepositoryMockRecorder) UpdateState(ctx, id, fired, lastFiredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateState", reflect.TypeOf((*MockAlertRepository)(nil).UpdateState), ctx, id, fired, lastFiredAt)
+}
+
+// UpdateThreshold mocks base method.
+func (m *MockAlertRepository) UpdateThreshold(ctx context.Context, id int64, threshold float64, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateThreshold", ctx, id, threshold, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateThreshold indicates an expected call of UpdateThreshold.
+func (mr *MockAlertRepositoryMockRecorder) UpdateThreshold(ctx, id, threshold, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateThreshold", reflect.TypeOf((*MockAlertRepository)(nil).UpdateThreshold), ctx, id, threshold, contact)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: channels-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChannelRepository is a mock of ChannelRepository interface.
+type MockChannelRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChannelRepositoryMockRecorder
+}
+
+// MockChannelRepositoryMockRecorder is the mock recorder for MockChannelRepository.
+type MockChannelRepositoryMockRecorder struct {
+	mock *MockChannelRepository
+}
+
+// NewMockChannelRepository creates a new mock instance.
+func NewMockChannelRepository(ctrl *gomock.Controller) *MockChannelRepository {
+	mock := &MockChannelRepository{ctrl: ctrl}
+	mock.recorder = &MockChannelRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChannelRepository) EXPECT() *MockChannelRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FetchByUser mocks base method.
+func (m *MockChannelRepository) FetchByUser(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByUser", ctx, contact)
+	ret0, _ := ret[0].([]models.UserChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByUser indicates an expected call of FetchByUser.
+func (mr *MockChannelRepositoryMockRecorder) FetchByUser(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByUser", reflect.TypeOf((*MockChannelRepository)(nil).FetchByUser), ctx, contact)
+}
+
+// FetchEnabled mocks base method.
+func (m *MockChannelRepository) FetchEnabled(ctx context.Context) ([]models.UserChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchEnabled", ctx)
+	ret0, _ := ret[0].([]models.UserChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchEnabled indicates an expected call of FetchEnabled.
+func (mr *MockChannelRepositoryMockRecorder) FetchEnabled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEnabled", reflect.TypeOf((*MockChannelRepository)(nil).FetchEnabled), ctx)
+}
+
+// Set mocks base method.
+func (m *MockChannelRepository) Set(ctx context.Context, channel models.UserChannel, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, channel, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockChannelRepositoryMockRecorder) Set(ctx, channel, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockChannelRepository)(nil).Set), ctx, channel, contact)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: chat-states-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChatStateRepository is a mock of ChatStateRepository interface.
+type MockChatStateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatStateRepositoryMockRecorder
+}
+
+// MockChatStateRepositoryMockRecorder is the mock recorder for MockChatStateRepository.
+type MockChatStateRepositoryMockRecorder struct {
+	mock *MockChatStateRepository
+}
+
+// NewMockChatStateRepository creates a new mock instance.
+func NewMockChatStateRepository(ctrl *gomock.Controller) *MockChatStateRepository {
+	mock := &MockChatStateRepository{ctrl: ctrl}
+	mock.recorder = &MockChatStateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatStateRepository) EXPECT() *MockChatStateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockChatStateRepository) Delete(ctx context.Context, chatId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, chatId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockChatStateRepositoryMockRecorder) Delete(ctx, chatId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChatStateRepository)(nil).Delete), ctx, chatId)
+}
+
+// Get mocks base method.
+func (m *MockChatStateRepository) Get(ctx context.Context, chatId int64) (*models.ChatState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, chatId)
+	ret0, _ := ret[0].(*models.ChatState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockChatStateRepositoryMockRecorder) Get(ctx, chatId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChatStateRepository)(nil).Get), ctx, chatId)
+}
+
+// Set mocks base method.
+func (m *MockChatStateRepository) Set(ctx context.Context, state models.ChatState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockChatStateRepositoryMockRecorder) Set(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockChatStateRepository)(nil).Set), ctx, state)
+}
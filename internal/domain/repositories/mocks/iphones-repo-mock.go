@@ -35,6 +35,37 @@ func (m *MockIPhoneRepository) EXPECT() *MockIPhoneRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Fetch mocks base method.
+func (m *MockIPhoneRepository) Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, filter)
+	ret0, _ := ret[0].([]models.IPhone)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockIPhoneRepositoryMockRecorder) Fetch(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockIPhoneRepository)(nil).Fetch), ctx, filter)
+}
+
+// FetchActive mocks base method.
+func (m *MockIPhoneRepository) FetchActive(ctx context.Context) ([]models.IPhone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchActive", ctx)
+	ret0, _ := ret[0].([]models.IPhone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchActive indicates an expected call of FetchActive.
+func (mr *MockIPhoneRepositoryMockRecorder) FetchActive(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchActive", reflect.TypeOf((*MockIPhoneRepository)(nil).FetchActive), ctx)
+}
+
 // Get mocks base method.
 func (m *MockIPhoneRepository) Get(ctx context.Context, id string) (*models.IPhone, error) {
 	m.ctrl.T.Helper()
@@ -51,16 +82,57 @@ func (mr *MockIPhoneRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Cal
 }
 
 // Update mocks base method.
-func (m *MockIPhoneRepository) Update(ctx context.Context, id string, price float64) (*models.IPhone, error) {
+func (m *MockIPhoneRepository) Update(ctx context.Context, id string, update models.IPhoneUpdate) (*models.IPhone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, price)
+	ret := m.ctrl.Call(m, "Update", ctx, id, update)
 	ret0, _ := ret[0].(*models.IPhone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockIPhoneRepositoryMockRecorder) Update(ctx, id, price interface{}) *gomock.Call {
+func (mr *MockIPhoneRepositoryMockRecorder) Update(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIPhoneRepository)(nil).Update), ctx, id, update)
+}
+
+// Mockscanner is a mock of scanner interface.
+type Mockscanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockscannerMockRecorder
+}
+
+// MockscannerMockRecorder is the mock recorder for Mockscanner.
+type MockscannerMockRecorder struct {
+	mock *Mockscanner
+}
+
+// NewMockscanner creates a new mock instance.
+func NewMockscanner(ctrl *gomock.Controller) *Mockscanner {
+	mock := &Mockscanner{ctrl: ctrl}
+	mock.recorder = &MockscannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscanner) EXPECT() *MockscannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *Mockscanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockscannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIPhoneRepository)(nil).Update), ctx, id, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*Mockscanner)(nil).Scan), dest...)
 }
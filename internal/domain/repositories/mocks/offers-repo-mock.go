@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: offers-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOfferRepository is a mock of OfferRepository interface.
+type MockOfferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferRepositoryMockRecorder
+}
+
+// MockOfferRepositoryMockRecorder is the mock recorder for MockOfferRepository.
+type MockOfferRepositoryMockRecorder struct {
+	mock *MockOfferRepository
+}
+
+// NewMockOfferRepository creates a new mock instance.
+func NewMockOfferRepository(ctrl *gomock.Controller) *MockOfferRepository {
+	mock := &MockOfferRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferRepository) EXPECT() *MockOfferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FetchBest mocks base method.
+func (m *MockOfferRepository) FetchBest(ctx context.Context) (map[string]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchBest", ctx)
+	ret0, _ := ret[0].(map[string]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchBest indicates an expected call of FetchBest.
+func (mr *MockOfferRepositoryMockRecorder) FetchBest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBest", reflect.TypeOf((*MockOfferRepository)(nil).FetchBest), ctx)
+}
+
+// FetchByIPhone mocks base method.
+func (m *MockOfferRepository) FetchByIPhone(ctx context.Context, iphoneId string) ([]models.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByIPhone", ctx, iphoneId)
+	ret0, _ := ret[0].([]models.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByIPhone indicates an expected call of FetchByIPhone.
+func (mr *MockOfferRepositoryMockRecorder) FetchByIPhone(ctx, iphoneId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByIPhone", reflect.TypeOf((*MockOfferRepository)(nil).FetchByIPhone), ctx, iphoneId)
+}
+
+// Update mocks base method.
+func (m *MockOfferRepository) Update(ctx context.Context, offer models.Offer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, offer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOfferRepositoryMockRecorder) Update(ctx, offer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOfferRepository)(nil).Update), ctx, offer)
+}
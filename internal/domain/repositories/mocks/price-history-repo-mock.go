@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: price-history-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPriceHistoryRepository is a mock of PriceHistoryRepository interface.
+type MockPriceHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceHistoryRepositoryMockRecorder
+}
+
+// MockPriceHistoryRepositoryMockRecorder is the mock recorder for MockPriceHistoryRepository.
+type MockPriceHistoryRepositoryMockRecorder struct {
+	mock *MockPriceHistoryRepository
+}
+
+// NewMockPriceHistoryRepository creates a new mock instance.
+func NewMockPriceHistoryRepository(ctrl *gomock.Controller) *MockPriceHistoryRepository {
+	mock := &MockPriceHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockPriceHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceHistoryRepository) EXPECT() *MockPriceHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPriceHistoryRepository) Create(ctx context.Context, record *models.PriceRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPriceHistoryRepositoryMockRecorder) Create(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPriceHistoryRepository)(nil).Create), ctx, record)
+}
+
+// FetchByRange mocks base method.
+func (m *MockPriceHistoryRepository) FetchByRange(ctx context.Context, iphoneId string, from, to time.Time) ([]models.PriceRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByRange", ctx, iphoneId, from, to)
+	ret0, _ := ret[0].([]models.PriceRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByRange indicates an expected call of FetchByRange.
+func (mr *MockPriceHistoryRepositoryMockRecorder) FetchByRange(ctx, iphoneId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByRange", reflect.TypeOf((*MockPriceHistoryRepository)(nil).FetchByRange), ctx, iphoneId, from, to)
+}
+
+// FetchExtremes mocks base method.
+func (m *MockPriceHistoryRepository) FetchExtremes(ctx context.Context, iphoneId string) (*models.PriceExtremes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExtremes", ctx, iphoneId)
+	ret0, _ := ret[0].(*models.PriceExtremes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExtremes indicates an expected call of FetchExtremes.
+func (mr *MockPriceHistoryRepositoryMockRecorder) FetchExtremes(ctx, iphoneId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExtremes", reflect.TypeOf((*MockPriceHistoryRepository)(nil).FetchExtremes), ctx, iphoneId)
+}
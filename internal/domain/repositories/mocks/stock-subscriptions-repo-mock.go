@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: stock-subscriptions-repo.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockStockSubscriptionRepository is a mock of StockSubscriptionRepository interface.
+type MockStockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockSubscriptionRepositoryMockRecorder
+}
+
+// MockStockSubscriptionRepositoryMockRecorder is the mock recorder for MockStockSubscriptionRepository.
+type MockStockSubscriptionRepositoryMockRecorder struct {
+	mock *MockStockSubscriptionRepository
+}
+
+// NewMockStockSubscriptionRepository creates a new mock instance.
+func NewMockStockSubscriptionRepository(ctrl *gomock.Controller) *MockStockSubscriptionRepository {
+	mock := &MockStockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockStockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockSubscriptionRepository) EXPECT() *MockStockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockStockSubscriptionRepository) Create(ctx context.Context, iphoneId string, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, iphoneId, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStockSubscriptionRepositoryMockRecorder) Create(ctx, iphoneId, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStockSubscriptionRepository)(nil).Create), ctx, iphoneId, contact)
+}
+
+// Delete mocks base method.
+func (m *MockStockSubscriptionRepository) Delete(ctx context.Context, iphoneId string, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, iphoneId, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStockSubscriptionRepositoryMockRecorder) Delete(ctx, iphoneId, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStockSubscriptionRepository)(nil).Delete), ctx, iphoneId, contact)
+}
+
+// DeleteByIPhoneAndUsers mocks base method.
+func (m *MockStockSubscriptionRepository) DeleteByIPhoneAndUsers(ctx context.Context, iphoneId string, userIds []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByIPhoneAndUsers", ctx, iphoneId, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByIPhoneAndUsers indicates an expected call of DeleteByIPhoneAndUsers.
+func (mr *MockStockSubscriptionRepositoryMockRecorder) DeleteByIPhoneAndUsers(ctx, iphoneId, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByIPhoneAndUsers", reflect.TypeOf((*MockStockSubscriptionRepository)(nil).DeleteByIPhoneAndUsers), ctx, iphoneId, userIds)
+}
+
+// FetchSubscribers mocks base method.
+func (m *MockStockSubscriptionRepository) FetchSubscribers(ctx context.Context, iphoneId string) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSubscribers", ctx, iphoneId)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSubscribers indicates an expected call of FetchSubscribers.
+func (mr *MockStockSubscriptionRepositoryMockRecorder) FetchSubscribers(ctx, iphoneId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSubscribers", reflect.TypeOf((*MockStockSubscriptionRepository)(nil).FetchSubscribers), ctx, iphoneId)
+}
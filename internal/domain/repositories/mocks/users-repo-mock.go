@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockUserRepository is a mock of UserRepository interface.
@@ -36,18 +37,18 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 }
 
 // CheckChatId mocks base method.
-func (m *MockUserRepository) CheckChatId(ctx context.Context, op, telegram string, chatId int64) (bool, error) {
+func (m *MockUserRepository) CheckChatId(ctx context.Context, op string, telegramId, chatId int64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckChatId", ctx, op, telegram, chatId)
+	ret := m.ctrl.Call(m, "CheckChatId", ctx, op, telegramId, chatId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckChatId indicates an expected call of CheckChatId.
-func (mr *MockUserRepositoryMockRecorder) CheckChatId(ctx, op, telegram, chatId interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) CheckChatId(ctx, op, telegramId, chatId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckChatId", reflect.TypeOf((*MockUserRepository)(nil).CheckChatId), ctx, op, telegram, chatId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckChatId", reflect.TypeOf((*MockUserRepository)(nil).CheckChatId), ctx, op, telegramId, chatId)
 }
 
 // Create mocks base method.
@@ -64,32 +65,62 @@ func (mr *MockUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
 }
 
-// DropChatId mocks base method.
-func (m *MockUserRepository) DropChatId(ctx context.Context, telegram string, chatId int64) error {
+// CreateLinkCode mocks base method.
+func (m *MockUserRepository) CreateLinkCode(ctx context.Context, code models.LinkCode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DropChatId", ctx, telegram, chatId)
+	ret := m.ctrl.Call(m, "CreateLinkCode", ctx, code)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DropChatId indicates an expected call of DropChatId.
-func (mr *MockUserRepositoryMockRecorder) DropChatId(ctx, telegram, chatId interface{}) *gomock.Call {
+// CreateLinkCode indicates an expected call of CreateLinkCode.
+func (mr *MockUserRepositoryMockRecorder) CreateLinkCode(ctx, code interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropChatId", reflect.TypeOf((*MockUserRepository)(nil).DropChatId), ctx, telegram, chatId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkCode", reflect.TypeOf((*MockUserRepository)(nil).CreateLinkCode), ctx, code)
 }
 
-// DropDesiredPrice mocks base method.
-func (m *MockUserRepository) DropDesiredPrice(ctx context.Context, chatId int64) error {
+// DropChatId mocks base method.
+func (m *MockUserRepository) DropChatId(ctx context.Context, telegramId, chatId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DropDesiredPrice", ctx, chatId)
+	ret := m.ctrl.Call(m, "DropChatId", ctx, telegramId, chatId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DropDesiredPrice indicates an expected call of DropDesiredPrice.
-func (mr *MockUserRepositoryMockRecorder) DropDesiredPrice(ctx, chatId interface{}) *gomock.Call {
+// DropChatId indicates an expected call of DropChatId.
+func (mr *MockUserRepositoryMockRecorder) DropChatId(ctx, telegramId, chatId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropChatId", reflect.TypeOf((*MockUserRepository)(nil).DropChatId), ctx, telegramId, chatId)
+}
+
+// FetchByApiToken mocks base method.
+func (m *MockUserRepository) FetchByApiToken(ctx context.Context, tokenHash string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByApiToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByApiToken indicates an expected call of FetchByApiToken.
+func (mr *MockUserRepositoryMockRecorder) FetchByApiToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByApiToken", reflect.TypeOf((*MockUserRepository)(nil).FetchByApiToken), ctx, tokenHash)
+}
+
+// FetchByTelegramId mocks base method.
+func (m *MockUserRepository) FetchByTelegramId(ctx context.Context, telegramId int64) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByTelegramId", ctx, telegramId)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByTelegramId indicates an expected call of FetchByTelegramId.
+func (mr *MockUserRepositoryMockRecorder) FetchByTelegramId(ctx, telegramId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropDesiredPrice", reflect.TypeOf((*MockUserRepository)(nil).DropDesiredPrice), ctx, chatId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByTelegramId", reflect.TypeOf((*MockUserRepository)(nil).FetchByTelegramId), ctx, telegramId)
 }
 
 // FetchContacts mocks base method.
@@ -107,30 +138,60 @@ func (mr *MockUserRepositoryMockRecorder) FetchContacts(ctx interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchContacts", reflect.TypeOf((*MockUserRepository)(nil).FetchContacts), ctx)
 }
 
-// SetChatId mocks base method.
-func (m *MockUserRepository) SetChatId(ctx context.Context, telegram string, chatId int64) error {
+// LinkByCode mocks base method.
+func (m *MockUserRepository) LinkByCode(ctx context.Context, code string, telegramId, chatId int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkByCode", ctx, code, telegramId, chatId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkByCode indicates an expected call of LinkByCode.
+func (mr *MockUserRepositoryMockRecorder) LinkByCode(ctx, code, telegramId, chatId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkByCode", reflect.TypeOf((*MockUserRepository)(nil).LinkByCode), ctx, code, telegramId, chatId)
+}
+
+// LinkExisting mocks base method.
+func (m *MockUserRepository) LinkExisting(ctx context.Context, telegramId, chatId int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkExisting", ctx, telegramId, chatId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkExisting indicates an expected call of LinkExisting.
+func (mr *MockUserRepositoryMockRecorder) LinkExisting(ctx, telegramId, chatId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkExisting", reflect.TypeOf((*MockUserRepository)(nil).LinkExisting), ctx, telegramId, chatId)
+}
+
+// SetApiToken mocks base method.
+func (m *MockUserRepository) SetApiToken(ctx context.Context, userId uuid.UUID, tokenHash string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetChatId", ctx, telegram, chatId)
+	ret := m.ctrl.Call(m, "SetApiToken", ctx, userId, tokenHash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetChatId indicates an expected call of SetChatId.
-func (mr *MockUserRepositoryMockRecorder) SetChatId(ctx, telegram, chatId interface{}) *gomock.Call {
+// SetApiToken indicates an expected call of SetApiToken.
+func (mr *MockUserRepositoryMockRecorder) SetApiToken(ctx, userId, tokenHash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatId", reflect.TypeOf((*MockUserRepository)(nil).SetChatId), ctx, telegram, chatId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetApiToken", reflect.TypeOf((*MockUserRepository)(nil).SetApiToken), ctx, userId, tokenHash)
 }
 
-// SetDesiredPrice mocks base method.
-func (m *MockUserRepository) SetDesiredPrice(ctx context.Context, chatId int64, price float64) error {
+// SetChatId mocks base method.
+func (m *MockUserRepository) SetChatId(ctx context.Context, telegramId, chatId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetDesiredPrice", ctx, chatId, price)
+	ret := m.ctrl.Call(m, "SetChatId", ctx, telegramId, chatId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetDesiredPrice indicates an expected call of SetDesiredPrice.
-func (mr *MockUserRepositoryMockRecorder) SetDesiredPrice(ctx, chatId, price interface{}) *gomock.Call {
+// SetChatId indicates an expected call of SetChatId.
+func (mr *MockUserRepositoryMockRecorder) SetChatId(ctx, telegramId, chatId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDesiredPrice", reflect.TypeOf((*MockUserRepository)(nil).SetDesiredPrice), ctx, chatId, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatId", reflect.TypeOf((*MockUserRepository)(nil).SetChatId), ctx, telegramId, chatId)
 }
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=offers-repo.go -destination=mocks/offers-repo-mock.go
+type OfferRepository interface {
+	FetchByIPhone(ctx context.Context, iphoneId string) ([]models.Offer, error)
+	FetchBest(ctx context.Context) (map[string]models.Offer, error)
+	Update(ctx context.Context, offer models.Offer) error
+}
+
+type offerRepository struct {
+	Storage *storage.Storage
+}
+
+func NewOfferRepository(s *storage.Storage) OfferRepository {
+	return &offerRepository{
+		Storage: s,
+	}
+}
+
+const offersRepo = "offerRepository."
+
+func (or *offerRepository) FetchByIPhone(ctx context.Context, iphoneId string) ([]models.Offer, error) {
+	op := offersRepo + "FetchByIPhone"
+	query := "SELECT id, iphone_id, source, url, price, old_price, installment, currency, sku, availability, checked_at FROM offers WHERE iphone_id = $1 ORDER BY id"
+	offers := []models.Offer{}
+	res, err := or.Storage.DB.QueryContext(ctx, query, iphoneId)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var offer models.Offer
+		if err := res.Scan(
+			&offer.Id,
+			&offer.IPhoneId,
+			&offer.Source,
+			&offer.Url,
+			&offer.Price,
+			&offer.OldPrice,
+			&offer.Installment,
+			&offer.Currency,
+			&offer.Sku,
+			&offer.Availability,
+			&offer.CheckedAt,
+		); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		offers = append(offers, offer)
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return offers, nil
+}
+
+// FetchBest returns the cheapest checked offer in stock or on preorder for every iphone.
+func (or *offerRepository) FetchBest(ctx context.Context) (map[string]models.Offer, error) {
+	op := offersRepo + "FetchBest"
+	query := `SELECT id, iphone_id, source, url, price, old_price, installment, currency, sku, availability, checked_at FROM offers
+		WHERE checked_at IS NOT NULL AND price > 0 AND availability != $1 ORDER BY iphone_id, price, id`
+	best := map[string]models.Offer{}
+	res, err := or.Storage.DB.QueryContext(ctx, query, models.AvailabilityOutOfStock)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var offer models.Offer
+		if err := res.Scan(
+			&offer.Id,
+			&offer.IPhoneId,
+			&offer.Source,
+			&offer.Url,
+			&offer.Price,
+			&offer.OldPrice,
+			&offer.Installment,
+			&offer.Currency,
+			&offer.Sku,
+			&offer.Availability,
+			&offer.CheckedAt,
+		); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		if _, ok := best[offer.IPhoneId]; !ok {
+			best[offer.IPhoneId] = offer
+		}
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return best, nil
+}
+
+const updateOfferQuery = "UPDATE offers SET price = $1, old_price = $2, installment = $3, currency = $4, sku = $5, availability = $6, checked_at = $7 WHERE id = $8"
+
+func offerUpdateArgs(offer models.Offer) []any {
+	var checkedAt *time.Time
+	if offer.CheckedAt != nil {
+		t := offer.CheckedAt.UTC()
+		checkedAt = &t
+	}
+	return []any{offer.Price, offer.OldPrice, offer.Installment, offer.Currency, offer.Sku, offer.Availability, checkedAt, offer.Id}
+}
+
+func (or *offerRepository) Update(ctx context.Context, offer models.Offer) error {
+	op := offersRepo + "Update"
+	res, err := or.Storage.DB.ExecContext(ctx, updateOfferQuery, offerUpdateArgs(offer)...)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
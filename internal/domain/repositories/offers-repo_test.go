@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfferRepository_FetchByIPhone(t *testing.T) {
+	tests := []struct {
+		testName       string
+		iphoneId       string
+		expectedResult []models.Offer
+		expectedError  error
+	}{
+		{
+			testName: "success fetching",
+			iphoneId: "iphone-black-id",
+			expectedResult: []models.Offer{
+				{Id: 1, IPhoneId: "iphone-black-id", Source: "newton.by", Url: "https://newton.by/iphone-black-id", Availability: models.AvailabilityInStock},
+				{Id: 3, IPhoneId: "iphone-black-id", Source: "other.by", Url: "https://other.by/iphone-black-id", Availability: models.AvailabilityInStock},
+			},
+			expectedError: nil,
+		},
+		{
+			testName:       "success empty fetching",
+			iphoneId:       "iphone-blue-id",
+			expectedResult: []models.Offer{},
+			expectedError:  nil,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS offers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			url TEXT NOT NULL UNIQUE,
+			price NUMERIC NOT NULL DEFAULT 0,
+			old_price NUMERIC NOT NULL DEFAULT 0,
+			installment NUMERIC NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT '',
+			sku TEXT NOT NULL DEFAULT '',
+			availability TEXT NOT NULL DEFAULT 'in_stock',
+			checked_at DATETIME
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test offers table: %v", err)
+	}
+
+	query := "INSERT INTO offers (iphone_id, source, url) VALUES ($1, $2, $3)"
+	if _, err := storage.DB.Exec(query, "iphone-black-id", "newton.by", "https://newton.by/iphone-black-id"); err != nil {
+		t.Fatalf("failed to insert test offer data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-white-id", "newton.by", "https://newton.by/iphone-white-id"); err != nil {
+		t.Fatalf("failed to insert test offer data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "iphone-black-id", "other.by", "https://other.by/iphone-black-id"); err != nil {
+		t.Fatalf("failed to insert test offer data: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := NewOfferRepository(storage)
+			offers, err := repo.FetchByIPhone(context.Background(), tt.iphoneId)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, offers)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOfferRepository_Update(t *testing.T) {
+	checkedAt := time.Date(2025, 11, 19, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testName      string
+		id            int64
+		price         float64
+		expectedError error
+	}{
+		{
+			testName:      "success updating",
+			id:            1,
+			price:         3099.99,
+			expectedError: nil,
+		},
+		{
+			testName:      "not found",
+			id:            42,
+			price:         3099.99,
+			expectedError: errs.ErrNotFoundBase,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS offers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			url TEXT NOT NULL UNIQUE,
+			price NUMERIC NOT NULL DEFAULT 0,
+			old_price NUMERIC NOT NULL DEFAULT 0,
+			installment NUMERIC NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT '',
+			sku TEXT NOT NULL DEFAULT '',
+			availability TEXT NOT NULL DEFAULT 'in_stock',
+			checked_at DATETIME
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test offers table: %v", err)
+	}
+
+	if _, err := storage.DB.Exec("INSERT INTO offers (iphone_id, source, url) VALUES ($1, $2, $3)", "iphone-black-id", "newton.by", "https://newton.by/iphone-black-id"); err != nil {
+		t.Fatalf("failed to insert test offer data: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := NewOfferRepository(storage)
+			err := repo.Update(context.Background(), models.Offer{Id: tt.id, Price: tt.price, OldPrice: 3500, Installment: 150, Currency: "BYN", Sku: "MG6J4", Availability: models.AvailabilityOutOfStock, CheckedAt: &checkedAt})
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				offers, err := repo.FetchByIPhone(context.Background(), "iphone-black-id")
+				assert.NoError(t, err)
+				assert.Equal(t, tt.price, offers[0].Price)
+				assert.Equal(t, 3500.0, offers[0].OldPrice)
+				assert.Equal(t, 150.0, offers[0].Installment)
+				assert.Equal(t, "BYN", offers[0].Currency)
+				assert.Equal(t, "MG6J4", offers[0].Sku)
+				assert.Equal(t, models.AvailabilityOutOfStock, offers[0].Availability)
+				assert.True(t, checkedAt.Equal(*offers[0].CheckedAt))
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOfferRepository_FetchBest(t *testing.T) {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS offers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			url TEXT NOT NULL UNIQUE,
+			price NUMERIC NOT NULL DEFAULT 0,
+			old_price NUMERIC NOT NULL DEFAULT 0,
+			installment NUMERIC NOT NULL DEFAULT 0,
+			currency TEXT NOT NULL DEFAULT '',
+			sku TEXT NOT NULL DEFAULT '',
+			availability TEXT NOT NULL DEFAULT 'in_stock',
+			checked_at DATETIME
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test offers table: %v", err)
+	}
+
+	checkedAt := time.Date(2025, 11, 19, 15, 0, 0, 0, time.UTC)
+	query := "INSERT INTO offers (iphone_id, source, url, price, availability, checked_at) VALUES ($1, $2, $3, $4, $5, $6)"
+	offers := []struct {
+		iphoneId     string
+		source       string
+		price        float64
+		availability string
+		checkedAt    *time.Time
+	}{
+		{"iphone-black-id", "newton.by", 3100, models.AvailabilityInStock, &checkedAt},
+		{"iphone-black-id", "other.by", 2900, models.AvailabilityPreorder, &checkedAt},
+		{"iphone-black-id", "cheap.by", 2500, models.AvailabilityOutOfStock, &checkedAt},
+		{"iphone-white-id", "newton.by", 2000, models.AvailabilityInStock, nil},
+		{"iphone-green-id", "newton.by", 0, models.AvailabilityInStock, &checkedAt},
+	}
+	for _, o := range offers {
+		if _, err := storage.DB.Exec(query, o.iphoneId, o.source, "https://"+o.source+"/"+o.iphoneId, o.price, o.availability, o.checkedAt); err != nil {
+			t.Fatalf("failed to insert test offer data: %v", err)
+		}
+	}
+
+	repo := NewOfferRepository(storage)
+	best, err := repo.FetchBest(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, best, 1)
+	assert.Equal(t, "other.by", best["iphone-black-id"].Source)
+	assert.Equal(t, 2900.0, best["iphone-black-id"].Price)
+	assert.Equal(t, "https://other.by/iphone-black-id", best["iphone-black-id"].Url)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=outbox-repo.go -destination=mocks/outbox-repo-mock.go
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, messages []models.OutboxMessage) error
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+	Update(ctx context.Context, message *models.OutboxMessage) error
+}
+
+type outboxRepository struct {
+	Storage *storage.Storage
+}
+
+func NewOutboxRepository(s *storage.Storage) OutboxRepository {
+	return &outboxRepository{
+		Storage: s,
+	}
+}
+
+const outboxRepo = "outboxRepository."
+
+func (obr *outboxRepository) Enqueue(ctx context.Context, messages []models.OutboxMessage) error {
+	op := outboxRepo + "Enqueue"
+	tx, err := obr.Storage.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO outbox (channel, recipient, subject, body, status, attempts, next_retry_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $6)`)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	defer stmt.Close()
+	now := time.Now().UTC()
+	for _, m := range messages {
+		if _, err := stmt.ExecContext(ctx, m.Channel, m.Recipient, m.Subject, m.Body, models.OutboxStatusPending, now); err != nil {
+			return errs.NewAppError(op, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
+
+func (obr *outboxRepository) FetchDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	op := outboxRepo + "FetchDue"
+	query := `SELECT id, channel, recipient, subject, body, status, attempts, next_retry_at, last_error, created_at, sent_at FROM outbox
+		WHERE status = $1 AND next_retry_at <= $2 ORDER BY next_retry_at, id LIMIT $3`
+	res, err := obr.Storage.DB.QueryContext(ctx, query, models.OutboxStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	messages := []models.OutboxMessage{}
+	for res.Next() {
+		var m models.OutboxMessage
+		if err := res.Scan(
+			&m.Id,
+			&m.Channel,
+			&m.Recipient,
+			&m.Subject,
+			&m.Body,
+			&m.Status,
+			&m.Attempts,
+			&m.NextRetryAt,
+			&m.LastError,
+			&m.CreatedAt,
+			&m.SentAt,
+		); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		messages = append(messages, m)
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return messages, nil
+}
+
+func (obr *outboxRepository) Update(ctx context.Context, message *models.OutboxMessage) error {
+	op := outboxRepo + "Update"
+	query := "UPDATE outbox SET status = $1, attempts = $2, next_retry_at = $3, last_error = $4, sent_at = $5 WHERE id = $6"
+	var sentAt *time.Time
+	if message.SentAt != nil {
+		t := message.SentAt.UTC()
+		sentAt = &t
+	}
+	res, err := obr.Storage.DB.ExecContext(ctx, query, message.Status, message.Attempts, message.NextRetryAt.UTC(), message.LastError, sentAt, message.Id)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
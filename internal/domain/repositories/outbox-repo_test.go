@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareOutboxStorage(t *testing.T) *storage.Storage {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			channel TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			subject TEXT NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_retry_at DATETIME NOT NULL,
+			last_error TEXT,
+			created_at DATETIME NOT NULL,
+			sent_at DATETIME
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test outbox table: %v", err)
+	}
+	return storage
+}
+
+func TestOutboxRepository(t *testing.T) {
+	storage := prepareOutboxStorage(t)
+	repo := NewOutboxRepository(storage)
+	messages := []models.OutboxMessage{
+		{Channel: models.ChannelTelegram, Recipient: "111", Body: "цены"},
+		{Channel: models.ChannelEmail, Recipient: "sanya@gmail.com", Subject: "цены", Body: "<p>цены</p>"},
+	}
+
+	t.Run("success enqueueing", func(t *testing.T) {
+		assert.NoError(t, repo.Enqueue(context.Background(), messages))
+		due, err := repo.FetchDue(context.Background(), time.Now().Add(time.Second), 10)
+		assert.NoError(t, err)
+		assert.Len(t, due, 2)
+		assert.Equal(t, models.OutboxStatusPending, due[0].Status)
+		assert.Equal(t, "111", due[0].Recipient)
+		assert.Equal(t, "цены", due[1].Subject)
+	})
+
+	t.Run("success fetching with limit", func(t *testing.T) {
+		due, err := repo.FetchDue(context.Background(), time.Now().Add(time.Second), 1)
+		assert.NoError(t, err)
+		assert.Len(t, due, 1)
+	})
+
+	t.Run("success rescheduling and sending", func(t *testing.T) {
+		due, err := repo.FetchDue(context.Background(), time.Now().Add(time.Second), 10)
+		assert.NoError(t, err)
+		retry := due[0]
+		retry.Attempts = 1
+		retry.NextRetryAt = time.Now().Add(time.Hour)
+		retry.LastError = utils.StrToPtr("telegram is down")
+		assert.NoError(t, repo.Update(context.Background(), &retry))
+		sent := due[1]
+		now := time.Now()
+		sent.Status = models.OutboxStatusSent
+		sent.Attempts = 1
+		sent.SentAt = &now
+		assert.NoError(t, repo.Update(context.Background(), &sent))
+
+		due, err = repo.FetchDue(context.Background(), time.Now().Add(time.Second), 10)
+		assert.NoError(t, err)
+		assert.Len(t, due, 0)
+		due, err = repo.FetchDue(context.Background(), time.Now().Add(2*time.Hour), 10)
+		assert.NoError(t, err)
+		assert.Len(t, due, 1)
+		assert.Equal(t, 1, due[0].Attempts)
+		assert.Equal(t, "telegram is down", *due[0].LastError)
+	})
+
+	t.Run("not found updating", func(t *testing.T) {
+		err := repo.Update(context.Background(), &models.OutboxMessage{Id: 100500, Status: models.OutboxStatusSent})
+		assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	})
+}
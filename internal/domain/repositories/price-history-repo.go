@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+)
+
+//go:generate mockgen -source=price-history-repo.go -destination=mocks/price-history-repo-mock.go
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, record *models.PriceRecord) error
+	FetchByRange(ctx context.Context, iphoneId string, from, to time.Time) ([]models.PriceRecord, error)
+	FetchExtremes(ctx context.Context, iphoneId string) (*models.PriceExtremes, error)
+}
+
+type priceHistoryRepository struct {
+	Storage *storage.Storage
+}
+
+func NewPriceHistoryRepository(s *storage.Storage) PriceHistoryRepository {
+	return &priceHistoryRepository{
+		Storage: s,
+	}
+}
+
+const priceHistoryRepo = "priceHistoryRepository."
+
+const createPriceRecordQuery = "INSERT INTO price_history (iphone_id, source, price, available, created_at) VALUES ($1, $2, $3, $4, $5)"
+
+func priceRecordArgs(record *models.PriceRecord) []any {
+	return []any{record.IPhoneId, record.Source, record.Price, record.Available, record.CreatedAt.UTC()}
+}
+
+func (phr *priceHistoryRepository) Create(ctx context.Context, record *models.PriceRecord) error {
+	op := priceHistoryRepo + "Create"
+	res, err := phr.Storage.DB.ExecContext(ctx, createPriceRecordQuery, priceRecordArgs(record)...)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	record.Id = id
+	return nil
+}
+
+func (phr *priceHistoryRepository) FetchByRange(ctx context.Context, iphoneId string, from, to time.Time) ([]models.PriceRecord, error) {
+	op := priceHistoryRepo + "FetchByRange"
+	query := `SELECT id, iphone_id, source, price, available, created_at FROM price_history
+		WHERE iphone_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY created_at, id`
+	records := []models.PriceRecord{}
+	res, err := phr.Storage.DB.QueryContext(ctx, query, iphoneId, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var record models.PriceRecord
+		if err := res.Scan(
+			&record.Id,
+			&record.IPhoneId,
+			&record.Source,
+			&record.Price,
+			&record.Available,
+			&record.CreatedAt,
+		); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		records = append(records, record)
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return records, nil
+}
+
+func (phr *priceHistoryRepository) FetchExtremes(ctx context.Context, iphoneId string) (*models.PriceExtremes, error) {
+	op := priceHistoryRepo + "FetchExtremes"
+	query := "SELECT MIN(price), MAX(price) FROM price_history WHERE iphone_id = $1 AND price > 0"
+	var min, max sql.NullFloat64
+	if err := phr.Storage.DB.QueryRowContext(ctx, query, iphoneId).Scan(&min, &max); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	if !min.Valid || !max.Valid {
+		return nil, errs.ErrNotFound(op)
+	}
+	return &models.PriceExtremes{
+		Min: min.Float64,
+		Max: max.Float64,
+	}, nil
+}
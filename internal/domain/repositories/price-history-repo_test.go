@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceHistoryRepository_FetchByRange(t *testing.T) {
+	now := time.Date(2025, 11, 12, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testName       string
+		iphoneId       string
+		from           time.Time
+		to             time.Time
+		expectedResult []models.PriceRecord
+		expectedError  error
+	}{
+		{
+			testName: "success fetching range",
+			iphoneId: "iphone-black-id",
+			from:     now.Add(-48 * time.Hour),
+			to:       now,
+			expectedResult: []models.PriceRecord{
+				{
+					Id:        2,
+					IPhoneId:  "iphone-black-id",
+					Source:    "newton.by",
+					Price:     950,
+					Available: true,
+					CreatedAt: now.Add(-24 * time.Hour),
+				},
+				{
+					Id:        3,
+					IPhoneId:  "iphone-black-id",
+					Source:    "newton.by",
+					Price:     900,
+					Available: false,
+					CreatedAt: now.Add(-time.Hour),
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			testName:       "success empty range",
+			iphoneId:       "iphone-black-id",
+			from:           now.Add(time.Hour),
+			to:             now.Add(2 * time.Hour),
+			expectedResult: []models.PriceRecord{},
+			expectedError:  nil,
+		},
+		{
+			testName:       "success unknown iphone",
+			iphoneId:       "iphone-white-id",
+			from:           now.Add(-72 * time.Hour),
+			to:             now,
+			expectedResult: []models.PriceRecord{},
+			expectedError:  nil,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS price_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			price NUMERIC NOT NULL,
+			available BOOLEAN NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test price_history table: %v", err)
+	}
+
+	repo := NewPriceHistoryRepository(storage)
+	records := []models.PriceRecord{
+		{IPhoneId: "iphone-black-id", Source: "newton.by", Price: 1000, Available: true, CreatedAt: now.Add(-72 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Source: "newton.by", Price: 950, Available: true, CreatedAt: now.Add(-24 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Source: "newton.by", Price: 900, Available: false, CreatedAt: now.Add(-time.Hour)},
+	}
+	for _, r := range records {
+		if err := repo.Create(context.Background(), &r); err != nil {
+			t.Fatalf("failed to insert test price record: %v", err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			records, err := repo.FetchByRange(context.Background(), tt.iphoneId, tt.from, tt.to)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.expectedResult), len(records))
+				for i := range records {
+					assert.Equal(t, tt.expectedResult[i].Id, records[i].Id)
+					assert.Equal(t, tt.expectedResult[i].Price, records[i].Price)
+					assert.Equal(t, tt.expectedResult[i].Available, records[i].Available)
+					assert.True(t, tt.expectedResult[i].CreatedAt.Equal(records[i].CreatedAt))
+				}
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestPriceHistoryRepository_FetchExtremes(t *testing.T) {
+	now := time.Date(2025, 11, 12, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testName       string
+		iphoneId       string
+		expectedResult *models.PriceExtremes
+		expectedError  error
+	}{
+		{
+			testName:       "success fetching",
+			iphoneId:       "iphone-black-id",
+			expectedResult: &models.PriceExtremes{Min: 900, Max: 1000},
+			expectedError:  nil,
+		},
+		{
+			testName:       "not found",
+			iphoneId:       "iphone-white-id",
+			expectedResult: nil,
+			expectedError:  errs.ErrNotFoundBase,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS price_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			iphone_id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			price NUMERIC NOT NULL,
+			available BOOLEAN NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test price_history table: %v", err)
+	}
+
+	repo := NewPriceHistoryRepository(storage)
+	for i, price := range []float64{950, 1000, 0, 900} {
+		record := &models.PriceRecord{IPhoneId: "iphone-black-id", Source: "newton.by", Price: price, Available: price > 0, CreatedAt: now.Add(time.Duration(i) * time.Hour)}
+		if err := repo.Create(context.Background(), record); err != nil {
+			t.Fatalf("failed to insert test price record: %v", err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			extremes, err := repo.FetchExtremes(context.Background(), tt.iphoneId)
+			assert.Equal(t, tt.expectedResult, extremes)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -source=stock-subscriptions-repo.go -destination=mocks/stock-subscriptions-repo-mock.go
+type StockSubscriptionRepository interface {
+	Create(ctx context.Context, iphoneId string, contact models.Contacts) error
+	Delete(ctx context.Context, iphoneId string, contact models.Contacts) error
+	FetchSubscribers(ctx context.Context, iphoneId string) ([]models.User, error)
+	DeleteByIPhoneAndUsers(ctx context.Context, iphoneId string, userIds []uuid.UUID) error
+}
+
+type stockSubscriptionRepository struct {
+	Storage *storage.Storage
+}
+
+func NewStockSubscriptionRepository(s *storage.Storage) StockSubscriptionRepository {
+	return &stockSubscriptionRepository{
+		Storage: s,
+	}
+}
+
+const stockSubscriptionsRepo = "stockSubscriptionRepository."
+
+func (sr *stockSubscriptionRepository) Create(ctx context.Context, iphoneId string, contact models.Contacts) error {
+	op := stockSubscriptionsRepo + "Create"
+	query := "INSERT INTO stock_subscriptions (user_id, iphone_id, created_at) SELECT id, $1, $2 FROM users WHERE chat_id = $3 OR email = $4"
+	res, err := sr.Storage.DB.ExecContext(ctx, query, iphoneId, time.Now().UTC(), contact.ChatId, contact.Email)
+	if err != nil {
+		if storage.ErrorAlreadyExists(err) {
+			return errs.ErrAlreadyExists(op, err)
+		}
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (sr *stockSubscriptionRepository) Delete(ctx context.Context, iphoneId string, contact models.Contacts) error {
+	op := stockSubscriptionsRepo + "Delete"
+	query := "DELETE FROM stock_subscriptions WHERE iphone_id = $1 AND user_id IN (SELECT id FROM users WHERE chat_id = $2 OR email = $3)"
+	res, err := sr.Storage.DB.ExecContext(ctx, query, iphoneId, contact.ChatId, contact.Email)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	nr, _ := res.RowsAffected()
+	if nr == 0 {
+		return errs.ErrNotFound(op)
+	}
+	return nil
+}
+
+func (sr *stockSubscriptionRepository) FetchSubscribers(ctx context.Context, iphoneId string) ([]models.User, error) {
+	op := stockSubscriptionsRepo + "FetchSubscribers"
+	query := `SELECT u.id, u.name, COALESCE(u.email, ''), u.telegram, u.chat_id FROM stock_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.iphone_id = $1 ORDER BY s.id`
+	users := []models.User{}
+	res, err := sr.Storage.DB.QueryContext(ctx, query, iphoneId)
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	defer res.Close()
+	for res.Next() {
+		var user models.User
+		if err := res.Scan(
+			&user.Id,
+			&user.Name,
+			&user.Email,
+			&user.Telegram,
+			&user.ChatId,
+		); err != nil {
+			return nil, errs.NewAppError(op, err)
+		}
+		users = append(users, user)
+	}
+	if err := res.Err(); err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return users, nil
+}
+
+// DeleteByIPhoneAndUsers drops the subscriptions of the given users only, the rest keep waiting for a notification.
+func (sr *stockSubscriptionRepository) DeleteByIPhoneAndUsers(ctx context.Context, iphoneId string, userIds []uuid.UUID) error {
+	op := stockSubscriptionsRepo + "DeleteByIPhoneAndUsers"
+	tx, err := sr.Storage.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, "DELETE FROM stock_subscriptions WHERE iphone_id = $1 AND user_id = $2")
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	defer stmt.Close()
+	for _, userId := range userIds {
+		if _, err := stmt.ExecContext(ctx, iphoneId, userId); err != nil {
+			return errs.NewAppError(op, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/storage"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	stockSanyaId  = uuid.MustParse("5b1c7a0e-3d4f-4c8a-9e2b-1a6f0d7c8e91")
+	stockKirillId = uuid.MustParse("8e4d2c1b-7a6f-4e3d-b5c9-0f1e2d3c4b5a")
+)
+
+func prepareStockSubscriptionsStorage(t *testing.T) *storage.Storage {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			email TEXT NOT NULL UNIQUE,
+			telegram TEXT UNIQUE,
+			chat_id INTEGER UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS stock_subscriptions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			iphone_id TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			UNIQUE (user_id, iphone_id)
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test stock subscriptions tables: %v", err)
+	}
+	query := "INSERT INTO users (id, name, email, telegram, chat_id) VALUES ($1, $2, $3, $4, $5)"
+	if _, err := storage.DB.Exec(query, stockSanyaId, "sanya", "sanya@gmail.com", "tg1", 111); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, stockKirillId, "kirill", "kirill@gmail.com", nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data: %v", err)
+	}
+	return storage
+}
+
+func TestStockSubscriptionRepository_Create(t *testing.T) {
+	tests := []struct {
+		testName      string
+		iphoneId      string
+		contact       models.Contacts
+		expectedError error
+	}{
+		{
+			testName:      "success by chat id",
+			iphoneId:      "iphone-black-id",
+			contact:       models.Contacts{ChatId: utils.Int64ToPtr(111)},
+			expectedError: nil,
+		},
+		{
+			testName:      "success by email",
+			iphoneId:      "iphone-black-id",
+			contact:       models.Contacts{Email: "kirill@gmail.com"},
+			expectedError: nil,
+		},
+		{
+			testName:      "already exists",
+			iphoneId:      "iphone-black-id",
+			contact:       models.Contacts{Email: "sanya@gmail.com"},
+			expectedError: errs.ErrAlreadyExistsBase,
+		},
+		{
+			testName:      "user not found",
+			iphoneId:      "iphone-black-id",
+			contact:       models.Contacts{Email: "nobody@gmail.com"},
+			expectedError: errs.ErrNotFoundBase,
+		},
+	}
+
+	storage := prepareStockSubscriptionsStorage(t)
+	repo := NewStockSubscriptionRepository(storage)
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := repo.Create(context.Background(), tt.iphoneId, tt.contact)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestStockSubscriptionRepository_FetchSubscribers(t *testing.T) {
+	tests := []struct {
+		testName       string
+		iphoneId       string
+		expectedResult []models.User
+		expectedError  error
+	}{
+		{
+			testName: "success fetching",
+			iphoneId: "iphone-black-id",
+			expectedResult: []models.User{
+				{Id: stockSanyaId, Name: "sanya", Contacts: models.Contacts{Email: "sanya@gmail.com", Telegram: utils.StrToPtr("tg1"), ChatId: utils.Int64ToPtr(111)}},
+				{Id: stockKirillId, Name: "kirill", Contacts: models.Contacts{Email: "kirill@gmail.com"}},
+			},
+			expectedError: nil,
+		},
+		{
+			testName:       "success empty fetching",
+			iphoneId:       "iphone-white-id",
+			expectedResult: []models.User{},
+			expectedError:  nil,
+		},
+	}
+
+	storage := prepareStockSubscriptionsStorage(t)
+	repo := NewStockSubscriptionRepository(storage)
+	if err := repo.Create(context.Background(), "iphone-black-id", models.Contacts{ChatId: utils.Int64ToPtr(111)}); err != nil {
+		t.Fatalf("failed to insert test subscription: %v", err)
+	}
+	if err := repo.Create(context.Background(), "iphone-black-id", models.Contacts{Email: "kirill@gmail.com"}); err != nil {
+		t.Fatalf("failed to insert test subscription: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			users, err := repo.FetchSubscribers(context.Background(), tt.iphoneId)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, users)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+
+	t.Run("success deleting notified users only", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteByIPhoneAndUsers(context.Background(), "iphone-black-id", []uuid.UUID{stockSanyaId}))
+		users, err := repo.FetchSubscribers(context.Background(), "iphone-black-id")
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, stockKirillId, users[0].Id)
+	})
+}
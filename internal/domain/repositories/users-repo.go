@@ -6,17 +6,24 @@ import (
 	"iFall/internal/domain/models"
 	"iFall/pkg/errs"
 	"iFall/pkg/storage"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=users-repo.go -destination=mocks/users-repo-mock.go
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	FetchContacts(ctx context.Context) ([]models.Contacts, error)
-	SetChatId(ctx context.Context, telegram string, chatId int64) error
-	DropChatId(ctx context.Context, telegram string, chatId int64) error
-	CheckChatId(ctx context.Context, op, telegram string, chatId int64) (bool, error)
-	SetDesiredPrice(ctx context.Context, chatId int64, price float64) error
-	DropDesiredPrice(ctx context.Context, chatId int64) error
+	CreateLinkCode(ctx context.Context, code models.LinkCode) error
+	LinkByCode(ctx context.Context, code string, telegramId int64, chatId int64) (string, error)
+	LinkExisting(ctx context.Context, telegramId int64, chatId int64) (string, error)
+	FetchByTelegramId(ctx context.Context, telegramId int64) (*models.User, error)
+	SetApiToken(ctx context.Context, userId uuid.UUID, tokenHash string) error
+	FetchByApiToken(ctx context.Context, tokenHash string) (*models.User, error)
+	SetChatId(ctx context.Context, telegramId int64, chatId int64) error
+	DropChatId(ctx context.Context, telegramId int64, chatId int64) error
+	CheckChatId(ctx context.Context, op string, telegramId int64, chatId int64) (bool, error)
 }
 
 type userRepository struct {
@@ -33,36 +40,162 @@ const usersRepo = "userRepository."
 
 func (ur *userRepository) Create(ctx context.Context, user *models.User) error {
 	op := usersRepo + "Create"
-	query := "INSERT INTO users (id, name, email, telegram) VALUES ($1, $2, $3, $4)"
-	if _, err := ur.Storage.DB.ExecContext(ctx, query, user.Id, user.Name, user.Email, user.Telegram); err != nil {
+	query := "INSERT INTO users (id, name, email, telegram, telegram_id, chat_id) VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)"
+	if _, err := ur.Storage.DB.ExecContext(ctx, query, user.Id, user.Name, user.Email, user.Telegram, user.TelegramId, user.ChatId); err != nil {
+		if storage.ErrorAlreadyExists(err) {
+			return errs.ErrAlreadyExists(op, err)
+		}
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
+
+// CreateLinkCode replaces previous codes of the user, so only the latest one can be redeemed.
+func (ur *userRepository) CreateLinkCode(ctx context.Context, code models.LinkCode) error {
+	op := usersRepo + "CreateLinkCode"
+	tx, err := ur.Storage.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return errs.NewAppError(op, err)
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, "DELETE FROM link_codes WHERE user_id = $1 OR expires_at <= $2", code.UserId, now); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	query := "INSERT INTO link_codes (code, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4)"
+	if _, err := tx.ExecContext(ctx, query, code.Code, code.UserId, code.ExpiresAt.UTC(), now); err != nil {
 		if storage.ErrorAlreadyExists(err) {
 			return errs.ErrAlreadyExists(op, err)
 		}
 		return errs.NewAppError(op, err)
 	}
+	if err := tx.Commit(); err != nil {
+		return errs.NewAppError(op, err)
+	}
 	return nil
 }
 
-func (ur *userRepository) DropChatId(ctx context.Context, telegram string, chatId int64) error {
+// LinkByCode redeems a link code and binds the telegram account and chat to its user.
+// The code is kept when the telegram account already belongs to another user.
+func (ur *userRepository) LinkByCode(ctx context.Context, code string, telegramId int64, chatId int64) (string, error) {
+	op := usersRepo + "LinkByCode"
+	tx, err := ur.Storage.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", errs.NewAppError(op, err)
+	}
+	defer tx.Rollback()
+	var userId string
+	query := "DELETE FROM link_codes WHERE code = $1 AND expires_at > $2 RETURNING user_id"
+	if err := tx.QueryRowContext(ctx, query, code, time.Now().UTC()).Scan(&userId); err != nil {
+		if err == storage.ErrNotFound() {
+			return "", errs.ErrNotFound(op)
+		}
+		return "", errs.NewAppError(op, err)
+	}
+	var name string
+	uQuery := "UPDATE users SET telegram_id = $1, chat_id = $2 WHERE id = $3 RETURNING name"
+	if err := tx.QueryRowContext(ctx, uQuery, telegramId, chatId, userId).Scan(&name); err != nil {
+		if err == storage.ErrNotFound() {
+			return "", errs.ErrNotFound(op)
+		}
+		if storage.ErrorAlreadyExists(err) {
+			return "", errs.ErrAlreadyExists(op, err)
+		}
+		return "", errs.NewAppError(op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", errs.NewAppError(op, err)
+	}
+	return name, nil
+}
+
+// LinkExisting binds the telegram account to a user stored before telegram ids, found by its chat.
+func (ur *userRepository) LinkExisting(ctx context.Context, telegramId int64, chatId int64) (string, error) {
+	op := usersRepo + "LinkExisting"
+	query := "UPDATE users SET telegram_id = $1 WHERE telegram_id IS NULL AND chat_id = $2 RETURNING name"
+	var name string
+	if err := ur.Storage.DB.QueryRowContext(ctx, query, telegramId, chatId).Scan(&name); err != nil {
+		if err == storage.ErrNotFound() {
+			return "", errs.ErrNotFound(op)
+		}
+		if storage.ErrorAlreadyExists(err) {
+			return "", errs.ErrAlreadyExists(op, err)
+		}
+		return "", errs.NewAppError(op, err)
+	}
+	return name, nil
+}
+
+const userColumns = "u.id, u.name, COALESCE(u.email, ''), u.telegram, u.telegram_id, u.chat_id"
+
+func scanUser(s scanner, user *models.User) error {
+	return s.Scan(
+		&user.Id,
+		&user.Name,
+		&user.Email,
+		&user.Telegram,
+		&user.TelegramId,
+		&user.ChatId,
+	)
+}
+
+func (ur *userRepository) FetchByTelegramId(ctx context.Context, telegramId int64) (*models.User, error) {
+	op := usersRepo + "FetchByTelegramId"
+	query := "SELECT " + userColumns + " FROM users u WHERE u.telegram_id = $1"
+	user := &models.User{}
+	if err := scanUser(ur.Storage.DB.QueryRowContext(ctx, query, telegramId), user); err != nil {
+		if err == storage.ErrNotFound() {
+			return nil, errs.ErrNotFound(op)
+		}
+		return nil, errs.NewAppError(op, err)
+	}
+	return user, nil
+}
+
+// SetApiToken replaces the previous token of the user.
+func (ur *userRepository) SetApiToken(ctx context.Context, userId uuid.UUID, tokenHash string) error {
+	op := usersRepo + "SetApiToken"
+	query := `INSERT INTO api_tokens (token_hash, user_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = excluded.created_at`
+	if _, err := ur.Storage.DB.ExecContext(ctx, query, tokenHash, userId, time.Now().UTC()); err != nil {
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
+
+func (ur *userRepository) FetchByApiToken(ctx context.Context, tokenHash string) (*models.User, error) {
+	op := usersRepo + "FetchByApiToken"
+	query := "SELECT " + userColumns + " FROM users u JOIN api_tokens t ON t.user_id = u.id WHERE t.token_hash = $1"
+	user := &models.User{}
+	if err := scanUser(ur.Storage.DB.QueryRowContext(ctx, query, tokenHash), user); err != nil {
+		if err == storage.ErrNotFound() {
+			return nil, errs.ErrNotFound(op)
+		}
+		return nil, errs.NewAppError(op, err)
+	}
+	return user, nil
+}
+
+func (ur *userRepository) DropChatId(ctx context.Context, telegramId int64, chatId int64) error {
 	op := usersRepo + "DropChatId"
-	exist, err := ur.CheckChatId(ctx, op, telegram, chatId)
+	exist, err := ur.CheckChatId(ctx, op, telegramId, chatId)
 	if err != nil {
 		return err
 	}
 	if exist {
 		return errs.ErrNotFound(op)
 	}
-	query := "UPDATE users SET chat_id = null WHERE telegram = $1 AND chat_id = $2"
-	if _, err := ur.Storage.DB.ExecContext(ctx, query, telegram, chatId); err != nil {
+	query := "UPDATE users SET chat_id = null WHERE telegram_id = $1 AND chat_id = $2"
+	if _, err := ur.Storage.DB.ExecContext(ctx, query, telegramId, chatId); err != nil {
 		return errs.NewAppError(op, err)
 	}
 
 	return nil
 }
 
-func (ur *userRepository) SetChatId(ctx context.Context, telegram string, chatId int64) error {
+func (ur *userRepository) SetChatId(ctx context.Context, telegramId int64, chatId int64) error {
 	op := usersRepo + "SetChatId"
-	exist, err := ur.CheckChatId(ctx, op, telegram, chatId)
+	exist, err := ur.CheckChatId(ctx, op, telegramId, chatId)
 	if err != nil {
 		return err
 	}
@@ -70,8 +203,8 @@ func (ur *userRepository) SetChatId(ctx context.Context, telegram string, chatId
 		return errs.ErrAlreadyExists(op, errors.New("same chat_id already exists"))
 	}
 
-	uQuery := "UPDATE users SET chat_id = $1 WHERE telegram = $2"
-	if _, err := ur.Storage.DB.ExecContext(ctx, uQuery, chatId, telegram); err != nil {
+	uQuery := "UPDATE users SET chat_id = $1 WHERE telegram_id = $2"
+	if _, err := ur.Storage.DB.ExecContext(ctx, uQuery, chatId, telegramId); err != nil {
 		return errs.NewAppError(op, err)
 	}
 
@@ -80,7 +213,7 @@ func (ur *userRepository) SetChatId(ctx context.Context, telegram string, chatId
 
 func (ur *userRepository) FetchContacts(ctx context.Context) ([]models.Contacts, error) {
 	op := usersRepo + "FetchContacts"
-	query := "SELECT email, chat_id, desired_price FROM users"
+	query := "SELECT COALESCE(email, ''), chat_id FROM users"
 	contacts := []models.Contacts{}
 	res, err := ur.Storage.DB.QueryContext(ctx, query)
 	if err != nil {
@@ -92,7 +225,6 @@ func (ur *userRepository) FetchContacts(ctx context.Context) ([]models.Contacts,
 		if err := res.Scan(
 			&contact.Email,
 			&contact.ChatId,
-			&contact.DesiredPrice,
 		); err != nil {
 			return nil, errs.NewAppError(op, err)
 		}
@@ -103,10 +235,10 @@ func (ur *userRepository) FetchContacts(ctx context.Context) ([]models.Contacts,
 
 var errIncorrectChatId = errors.New("incorrect chatId")
 
-func (ur *userRepository) CheckChatId(ctx context.Context, op, telegram string, chatId int64) (bool, error) {
+func (ur *userRepository) CheckChatId(ctx context.Context, op string, telegramId int64, chatId int64) (bool, error) {
 	var cid *int64
-	cQuery := "SELECT chat_id FROM users WHERE telegram = $1"
-	if err := ur.Storage.DB.QueryRowContext(ctx, cQuery, telegram).Scan(&cid); err != nil {
+	cQuery := "SELECT chat_id FROM users WHERE telegram_id = $1"
+	if err := ur.Storage.DB.QueryRowContext(ctx, cQuery, telegramId).Scan(&cid); err != nil {
 		if err == storage.ErrNotFound() {
 			return false, errs.ErrNotFound(op)
 		}
@@ -121,31 +253,3 @@ func (ur *userRepository) CheckChatId(ctx context.Context, op, telegram string,
 
 	return true, nil
 }
-
-func (ur *userRepository) SetDesiredPrice(ctx context.Context, chatId int64, price float64) error {
-	op := usersRepo + "SetDesiredPrice"
-	query := "UPDATE users SET desired_price = $1 WHERE chat_id = $2"
-	res, err := ur.Storage.DB.ExecContext(ctx, query, price, chatId)
-	if err != nil {
-		return errs.NewAppError(op, err)
-	}
-	nr, _ := res.RowsAffected()
-	if nr == 0 {
-		return errs.ErrNotFound(op)
-	}
-	return nil
-}
-
-func (ur *userRepository) DropDesiredPrice(ctx context.Context, chatId int64) error {
-	op := usersRepo + "DropDesiredPrice"
-	query := "UPDATE users SET desired_price = 0 WHERE chat_id = $1"
-	res, err := ur.Storage.DB.ExecContext(ctx, query, chatId)
-	if err != nil {
-		return errs.NewAppError(op, err)
-	}
-	nr, _ := res.RowsAffected()
-	if nr == 0 {
-		return errs.ErrNotFound(op)
-	}
-	return nil
-}
@@ -47,7 +47,7 @@ func TestUserRepository_Create(t *testing.T) {
 			expectedResult: nil,
 		},
 		{
-			testName: "already exists with name",
+			testName: "success creation with taken name",
 			userData: &models.User{
 				Id:   uuid.New(),
 				Name: "kir",
@@ -57,7 +57,7 @@ func TestUserRepository_Create(t *testing.T) {
 					ChatId:   nil,
 				},
 			},
-			expectedResult: errs.ErrAlreadyExistsBase,
+			expectedResult: nil,
 		},
 		{
 			testName: "already exists with email",
@@ -85,25 +85,53 @@ func TestUserRepository_Create(t *testing.T) {
 			},
 			expectedResult: errs.ErrAlreadyExistsBase,
 		},
+		{
+			testName: "success creation from bot without email",
+			userData: &models.User{
+				Id:   uuid.New(),
+				Name: "sanya",
+				Contacts: models.Contacts{
+					TelegramId: utils.Int64ToPtr(888),
+					ChatId:     utils.Int64ToPtr(888),
+				},
+			},
+			expectedResult: nil,
+		},
+		{
+			testName: "already exists with telegram id",
+			userData: &models.User{
+				Id:   uuid.New(),
+				Name: "sanya",
+				Contacts: models.Contacts{
+					TelegramId: utils.Int64ToPtr(777),
+					ChatId:     utils.Int64ToPtr(888),
+				},
+			},
+			expectedResult: errs.ErrAlreadyExistsBase,
+		},
 	}
 	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
+
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", nil, 2441); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "vova", nil, nil, 777, 777); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
@@ -128,35 +156,123 @@ func TestUserRepository_Create(t *testing.T) {
 	}
 }
 
-func TestUserRepository_DropChatId(t *testing.T) {
+func TestUserRepository_CreateLinkCode(t *testing.T) {
+	kirId, sanyaId := uuid.New(), uuid.New()
 	tests := []struct {
 		testName       string
-		telegram       string
-		chatId         int64
+		code           models.LinkCode
+		expectedCodes  []string
 		expectedResult error
 	}{
 		{
-			testName:       "success dropping",
-			telegram:       "kirtg",
-			chatId:         123123,
+			testName:       "success creation",
+			code:           models.LinkCode{Code: "KIRCODE1", UserId: kirId, ExpiresAt: time.Now().Add(time.Hour)},
+			expectedCodes:  []string{"KIRCODE1", "SANCODE1"},
 			expectedResult: nil,
 		},
 		{
-			testName:       "not found tg",
-			telegram:       "tg",
-			chatId:         123123,
+			testName:       "success replacing previous code",
+			code:           models.LinkCode{Code: "KIRCODE2", UserId: kirId, ExpiresAt: time.Now().Add(time.Hour)},
+			expectedCodes:  []string{"KIRCODE2", "SANCODE1"},
+			expectedResult: nil,
+		},
+		{
+			testName:       "already exists with code",
+			code:           models.LinkCode{Code: "SANCODE1", UserId: kirId, ExpiresAt: time.Now().Add(time.Hour)},
+			expectedCodes:  []string{"KIRCODE2", "SANCODE1"},
+			expectedResult: errs.ErrAlreadyExistsBase,
+		},
+	}
+
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS link_codes (
+			code TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test link_codes table: %v", err)
+	}
+
+	query := "INSERT INTO link_codes (code, user_id, expires_at, created_at) VALUES($1, $2, $3, $4)"
+	now := time.Now().UTC()
+	if _, err := storage.DB.Exec(query, "SANCODE1", sanyaId, now.Add(time.Hour), now); err != nil {
+		t.Fatalf("failed to insert test link code data in the table: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, "OLDCODE1", uuid.New(), now.Add(-time.Minute), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to insert test link code data in the table: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			repo := NewUserRepository(storage)
+			err := repo.CreateLinkCode(context.Background(), tt.code)
+			if tt.expectedResult == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedResult)
+			}
+			codes := []string{}
+			res, err := storage.DB.Query("SELECT code FROM link_codes ORDER BY code")
+			assert.NoError(t, err)
+			defer res.Close()
+			for res.Next() {
+				var code string
+				assert.NoError(t, res.Scan(&code))
+				codes = append(codes, code)
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+		})
+	}
+}
+
+func TestUserRepository_LinkByCode(t *testing.T) {
+	tests := []struct {
+		testName       string
+		code           string
+		telegramId     int64
+		chatId         int64
+		expectedName   string
+		expectedResult error
+	}{
+		{
+			testName:       "not found expired code",
+			code:           "OLDCODE1",
+			telegramId:     1,
+			chatId:         1,
 			expectedResult: errs.ErrNotFoundBase,
 		},
 		{
-			testName:       "not found chatid",
-			telegram:       "kirtg",
-			chatId:         111111,
+			testName:       "already exists with telegram id",
+			code:           "KIRCODE1",
+			telegramId:     777,
+			chatId:         1,
+			expectedResult: errs.ErrAlreadyExistsBase,
+		},
+		{
+			testName:       "success linking",
+			code:           "KIRCODE1",
+			telegramId:     1,
+			chatId:         1,
+			expectedName:   "kir",
+			expectedResult: nil,
+		},
+		{
+			testName:       "not found used code",
+			code:           "KIRCODE1",
+			telegramId:     2,
+			chatId:         2,
 			expectedResult: errs.ErrNotFoundBase,
 		},
 		{
-			testName:       "not found tg and chatid",
-			telegram:       "tg",
-			chatId:         111111,
+			testName:       "not found unknown code",
+			code:           "NOCODE",
+			telegramId:     2,
+			chatId:         2,
 			expectedResult: errs.ErrNotFoundBase,
 		},
 	}
@@ -165,29 +281,53 @@ func TestUserRepository_DropChatId(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS link_codes (
+			code TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
-		t.Fatalf("failed to create test users table: %v", err)
+		t.Fatalf("failed to create test tables: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
-
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", 123123, 2900); err != nil {
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
+	kirId, sanyaId := uuid.New(), uuid.New()
+	if _, err := storage.DB.Exec(query, kirId, "kir", "kir@gmail.com", "kirtg", nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
+	if _, err := storage.DB.Exec(query, sanyaId, "sanya", "san@gmail.com", "santg", 777, 777); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
+	cQuery := "INSERT INTO link_codes (code, user_id, expires_at, created_at) VALUES($1, $2, $3, $4)"
+	now := time.Now().UTC()
+	if _, err := storage.DB.Exec(cQuery, "KIRCODE1", kirId, now.Add(time.Hour), now); err != nil {
+		t.Fatalf("failed to insert test link code data in the table: %v", err)
+	}
+	if _, err := storage.DB.Exec(cQuery, "OLDCODE1", kirId, now.Add(-time.Minute), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to insert test link code data in the table: %v", err)
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			repo := NewUserRepository(storage)
-			err := repo.DropChatId(context.Background(), tt.telegram, tt.chatId)
+			name, err := repo.LinkByCode(context.Background(), tt.code, tt.telegramId, tt.chatId)
 			if tt.expectedResult == nil {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedName, name)
+				var telegramId, chatId int64
+				err := storage.DB.QueryRow("SELECT telegram_id, chat_id FROM users WHERE id = $1", kirId).Scan(&telegramId, &chatId)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.telegramId, telegramId)
+				assert.Equal(t, tt.chatId, chatId)
 			} else {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedResult)
@@ -196,30 +336,38 @@ func TestUserRepository_DropChatId(t *testing.T) {
 	}
 }
 
-func TestUserRepository_SetChatId(t *testing.T) {
+func TestUserRepository_LinkExisting(t *testing.T) {
 	tests := []struct {
 		testName       string
-		telegram       string
+		telegramId     int64
 		chatId         int64
+		expectedName   string
 		expectedResult error
 	}{
 		{
-			testName:       "success setting",
-			telegram:       "kirtg",
-			chatId:         456456,
+			testName:       "success linking by chat id",
+			telegramId:     111,
+			chatId:         111,
+			expectedName:   "kir",
 			expectedResult: nil,
 		},
 		{
-			testName:       "not found tg",
-			telegram:       "tg",
-			chatId:         111111,
+			testName:       "not found by username only",
+			telegramId:     222,
+			chatId:         222,
 			expectedResult: errs.ErrNotFoundBase,
 		},
 		{
-			testName:       "already exists",
-			telegram:       "santg",
-			chatId:         123123,
-			expectedResult: errs.ErrAlreadyExistsBase,
+			testName:       "not found already linked",
+			telegramId:     333,
+			chatId:         111,
+			expectedResult: errs.ErrNotFoundBase,
+		},
+		{
+			testName:       "not found unknown user",
+			telegramId:     444,
+			chatId:         444,
+			expectedResult: errs.ErrNotFoundBase,
 		},
 	}
 
@@ -227,33 +375,36 @@ func TestUserRepository_SetChatId(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", nil, 2800); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", 111, nil); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "san@gmail.com", "santg", 123123, 0); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "san@gmail.com", "santg", nil, nil); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			repo := NewUserRepository(storage)
-			err := repo.SetChatId(context.Background(), tt.telegram, tt.chatId)
+			name, err := repo.LinkExisting(context.Background(), tt.telegramId, tt.chatId)
 			if tt.expectedResult == nil {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedName, name)
+				_, err := repo.CheckChatId(context.Background(), "test", tt.telegramId, tt.chatId)
+				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedResult)
@@ -262,32 +413,86 @@ func TestUserRepository_SetChatId(t *testing.T) {
 	}
 }
 
-func TestUserRepository_FetchContacts(t *testing.T) {
+func TestUserRepository_ApiToken(t *testing.T) {
+	storage := storage.MustConnect(config.StorageConfig{Path: ":memory:", PingTimeout: time.Second})
+	schema := `
+		CREATE TABLE IF NOT EXISTS users (
+    		id TEXT PRIMARY KEY,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
+    		telegram TEXT UNIQUE,
+    		chat_id INTEGER UNIQUE,
+    		telegram_id INTEGER UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS api_tokens (
+    		token_hash TEXT PRIMARY KEY,
+    		user_id TEXT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    		created_at DATETIME NOT NULL
+		);
+	`
+	if _, err := storage.DB.Exec(schema); err != nil {
+		t.Fatalf("failed to create test tables: %v", err)
+	}
+	userId := uuid.New()
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
+	if _, err := storage.DB.Exec(query, userId, "sanya", nil, "santg", 111, 111); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepository(storage)
+
+	user, err := repo.FetchByTelegramId(ctx, 111)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, user.Id)
+	assert.Equal(t, "", user.Email)
+	_, err = repo.FetchByTelegramId(ctx, 222)
+	assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+
+	assert.NoError(t, repo.SetApiToken(ctx, userId, "first"))
+	user, err = repo.FetchByApiToken(ctx, "first")
+	assert.NoError(t, err)
+	assert.Equal(t, "sanya", user.Name)
+	assert.Equal(t, utils.Int64ToPtr(111), user.ChatId)
+
+	assert.NoError(t, repo.SetApiToken(ctx, userId, "second"))
+	_, err = repo.FetchByApiToken(ctx, "first")
+	assert.ErrorIs(t, err, errs.ErrNotFoundBase)
+	user, err = repo.FetchByApiToken(ctx, "second")
+	assert.NoError(t, err)
+	assert.Equal(t, userId, user.Id)
+}
+
+func TestUserRepository_DropChatId(t *testing.T) {
 	tests := []struct {
 		testName       string
-		expectedResult []models.Contacts
-		expectedError  error
+		telegramId     int64
+		chatId         int64
+		expectedResult error
 	}{
 		{
-			testName: "success fetching",
-			expectedResult: []models.Contacts{
-				{
-					Email:        "kiremail",
-					ChatId:       utils.Int64ToPtr(123123),
-					DesiredPrice: 2800.0,
-				},
-				{
-					Email:        "gusemail",
-					ChatId:       nil,
-					DesiredPrice: 0.0,
-				},
-			},
-			expectedError: nil,
+			testName:       "success dropping",
+			telegramId:     1,
+			chatId:         123123,
+			expectedResult: nil,
 		},
 		{
-			testName:       "success empty fetching",
-			expectedResult: []models.Contacts{},
-			expectedError:  nil,
+			testName:       "not found tg",
+			telegramId:     2,
+			chatId:         123123,
+			expectedResult: errs.ErrNotFoundBase,
+		},
+		{
+			testName:       "not found chatid",
+			telegramId:     1,
+			chatId:         111111,
+			expectedResult: errs.ErrNotFoundBase,
+		},
+		{
+			testName:       "not found tg and chatid",
+			telegramId:     2,
+			chatId:         111111,
+			expectedResult: errs.ErrNotFoundBase,
 		},
 	}
 
@@ -295,82 +500,61 @@ func TestUserRepository_FetchContacts(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
-
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "kirtg", 123123, 2800.0); err != nil {
-		t.Fatalf("failed to insert test user data in the table: %v", err)
-	}
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "gusemail", nil, nil, 0.0); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", 123123, 1); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
-			defer func() {
-				if _, err := storage.DB.Exec("DELETE FROM users"); err != nil {
-					t.Fatalf("failed to delete users: %v", err)
-				}
-			}()
 			repo := NewUserRepository(storage)
-			contacts, err := repo.FetchContacts(context.Background())
-			if tt.expectedError == nil {
+			err := repo.DropChatId(context.Background(), tt.telegramId, tt.chatId)
+			if tt.expectedResult == nil {
 				assert.NoError(t, err)
-				assert.ElementsMatch(t, contacts, tt.expectedResult)
 			} else {
 				assert.Error(t, err)
-				assert.ErrorIs(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, tt.expectedResult)
 			}
 		})
 	}
 }
 
-func TestUserRepository_CheckChatId(t *testing.T) {
+func TestUserRepository_SetChatId(t *testing.T) {
 	tests := []struct {
 		testName       string
-		telegram       string
+		telegramId     int64
 		chatId         int64
-		expectedResult bool
-		expectedError  error
+		expectedResult error
 	}{
 		{
-			testName:       "success exist",
-			telegram:       "tg1",
-			chatId:         123123,
-			expectedResult: false,
-			expectedError:  nil,
-		},
-		{
-			testName:       "success not exist",
-			telegram:       "tg2",
+			testName:       "success setting",
+			telegramId:     1,
 			chatId:         456456,
-			expectedResult: true,
-			expectedError:  nil,
+			expectedResult: nil,
 		},
 		{
-			testName:       "tg not found",
-			telegram:       "tg3",
-			chatId:         456456,
-			expectedResult: false,
-			expectedError:  errs.ErrNotFoundBase,
+			testName:       "not found tg",
+			telegramId:     3,
+			chatId:         111111,
+			expectedResult: errs.ErrNotFoundBase,
 		},
 		{
-			testName:       "incorrect chatid",
-			telegram:       "tg1",
-			chatId:         456456,
-			expectedResult: false,
-			expectedError:  errIncorrectChatId,
+			testName:       "already exists",
+			telegramId:     2,
+			chatId:         123123,
+			expectedResult: errs.ErrAlreadyExistsBase,
 		},
 	}
 
@@ -378,60 +562,69 @@ func TestUserRepository_CheckChatId(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "tg1", 123123, 2900); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kir@gmail.com", "kirtg", nil, 1); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "gusemail", "tg2", nil, 0); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "san@gmail.com", "santg", 123123, 2); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			repo := NewUserRepository(storage)
-			exist, err := repo.CheckChatId(context.Background(), "test-op", tt.telegram, tt.chatId)
-			if tt.expectedError == nil {
+			err := repo.SetChatId(context.Background(), tt.telegramId, tt.chatId)
+			if tt.expectedResult == nil {
 				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
-				assert.ErrorIs(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, tt.expectedResult)
 			}
-			assert.Equal(t, tt.expectedResult, exist)
 		})
 	}
 }
 
-func TestUserRepository_SetDesiredPrice(t *testing.T) {
+func TestUserRepository_FetchContacts(t *testing.T) {
 	tests := []struct {
-		testName      string
-		chatId        int64
-		desiredPrice  float64
-		expectedError error
+		testName       string
+		expectedResult []models.Contacts
+		expectedError  error
 	}{
 		{
-			testName:      "success setting",
-			chatId:        123123,
-			desiredPrice:  2800.0,
+			testName: "success fetching",
+			expectedResult: []models.Contacts{
+				{
+					Email:  "kiremail",
+					ChatId: utils.Int64ToPtr(123123),
+				},
+				{
+					Email:  "gusemail",
+					ChatId: nil,
+				},
+				{
+					Email:  "",
+					ChatId: utils.Int64ToPtr(555555),
+				},
+			},
 			expectedError: nil,
 		},
 		{
-			testName:      "not found",
-			chatId:        456456,
-			desiredPrice:  1500.0,
-			expectedError: errs.ErrNotFoundBase,
+			testName:       "success empty fetching",
+			expectedResult: []models.Contacts{},
+			expectedError:  nil,
 		},
 	}
 
@@ -439,54 +632,86 @@ func TestUserRepository_SetDesiredPrice(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
+
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "kirtg", 123123, nil); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
+
+	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "gusemail", nil, nil, nil); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "tg1", 123123, 0); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "vova", nil, nil, 555555, 555555); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
+			defer func() {
+				if _, err := storage.DB.Exec("DELETE FROM users"); err != nil {
+					t.Fatalf("failed to delete users: %v", err)
+				}
+			}()
 			repo := NewUserRepository(storage)
-			err := repo.SetDesiredPrice(context.Background(), tt.chatId, tt.desiredPrice)
+			contacts, err := repo.FetchContacts(context.Background())
 			if tt.expectedError == nil {
 				assert.NoError(t, err)
+				assert.ElementsMatch(t, contacts, tt.expectedResult)
 			} else {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedError)
 			}
 		})
 	}
-
 }
 
-func TestUserRepository_DropDesiredPrice(t *testing.T) {
+func TestUserRepository_CheckChatId(t *testing.T) {
 	tests := []struct {
-		testName      string
-		chatId        int64
-		desiredPrice  float64
-		expectedError error
+		testName       string
+		telegramId     int64
+		chatId         int64
+		expectedResult bool
+		expectedError  error
 	}{
 		{
-			testName:      "success dropping",
-			chatId:        123123,
-			expectedError: nil,
+			testName:       "success exist",
+			telegramId:     1,
+			chatId:         123123,
+			expectedResult: false,
+			expectedError:  nil,
+		},
+		{
+			testName:       "success not exist",
+			telegramId:     2,
+			chatId:         456456,
+			expectedResult: true,
+			expectedError:  nil,
+		},
+		{
+			testName:       "tg not found",
+			telegramId:     3,
+			chatId:         456456,
+			expectedResult: false,
+			expectedError:  errs.ErrNotFoundBase,
 		},
 		{
-			testName:      "not found",
-			chatId:        456456,
-			expectedError: errs.ErrNotFoundBase,
+			testName:       "incorrect chatid",
+			telegramId:     1,
+			chatId:         456456,
+			expectedResult: false,
+			expectedError:  errIncorrectChatId,
 		},
 	}
 
@@ -494,34 +719,38 @@ func TestUserRepository_DropDesiredPrice(t *testing.T) {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
     		id TEXT PRIMARY KEY,
-    		name TEXT NOT NULL UNIQUE,
-    		email TEXT NOT NULL UNIQUE,
+    		name TEXT NOT NULL,
+    		email TEXT UNIQUE,
     		telegram TEXT UNIQUE,
     		chat_id INTEGER UNIQUE,
-			desired_price NUMERIC NOT NULL DEFAULT 0
+    		telegram_id INTEGER UNIQUE
 		);
 	`
 	if _, err := storage.DB.Exec(schema); err != nil {
 		t.Fatalf("failed to create test users table: %v", err)
 	}
 
-	query := "INSERT INTO users (id, name, email, telegram, chat_id, desired_price) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users (id, name, email, telegram, chat_id, telegram_id) VALUES($1, $2, $3, $4, $5, $6)"
+
+	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "tg1", 123123, 1); err != nil {
+		t.Fatalf("failed to insert test user data in the table: %v", err)
+	}
 
-	if _, err := storage.DB.Exec(query, uuid.New(), "kir", "kiremail", "tg1", 123123, 0); err != nil {
+	if _, err := storage.DB.Exec(query, uuid.New(), "sanya", "gusemail", "tg2", nil, 2); err != nil {
 		t.Fatalf("failed to insert test user data in the table: %v", err)
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			repo := NewUserRepository(storage)
-			err := repo.DropDesiredPrice(context.Background(), tt.chatId)
+			exist, err := repo.CheckChatId(context.Background(), "test-op", tt.telegramId, tt.chatId)
 			if tt.expectedError == nil {
 				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedError)
 			}
+			assert.Equal(t, tt.expectedResult, exist)
 		})
 	}
-
 }
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/repositories"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"math"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=alerts-service.go -destination=mocks/alerts-service-mock.go
+type AlertService interface {
+	Create(ctx context.Context, contact models.Contacts, alert models.Alert) (*models.Alert, error)
+	Fetch(ctx context.Context, contact models.Contacts) ([]models.Alert, error)
+	Delete(ctx context.Context, contact models.Contacts, id int64) error
+	Evaluate(ctx context.Context, iphones []models.IPhone) ([]models.FiredAlert, error)
+	MarkFired(ctx context.Context, fired []models.FiredAlert) error
+}
+
+type alertService struct {
+	AlertRepository        repositories.AlertRepository
+	IPhoneRepository       repositories.IPhoneRepository
+	PriceHistoryRepository repositories.PriceHistoryRepository
+	Logger                 *logger.Logger
+}
+
+func NewAlertService(ar repositories.AlertRepository, ir repositories.IPhoneRepository, phr repositories.PriceHistoryRepository, l *logger.Logger) AlertService {
+	return &alertService{
+		AlertRepository:        ar,
+		IPhoneRepository:       ir,
+		PriceHistoryRepository: phr,
+		Logger:                 l,
+	}
+}
+
+const (
+	defaultDropWindow = 24
+	defaultLowWindow  = 30 * 24
+	defaultCooldown   = 24
+	checkGap          = 10 * time.Minute
+)
+
+const alertsPlace = "alertService."
+
+func (as *alertService) Create(ctx context.Context, contact models.Contacts, alert models.Alert) (*models.Alert, error) {
+	op := alertsPlace + "Create"
+	log := as.Logger.AddOp(op)
+	log.Info("creating alert")
+	if alert.IPhoneId != nil {
+		if _, err := as.IPhoneRepository.Get(ctx, *alert.IPhoneId); err != nil {
+			log.Error("failed to receive iphone", logger.Err(err))
+			return nil, errs.NewAppError(op, err)
+		}
+	}
+	alert.Direction = alertDirection(alert.Kind, alert.Direction)
+	alert.WindowHours = alertWindow(alert.Kind, alert.WindowHours)
+	alert.Repeat, alert.CooldownHours = alertRepeat(alert.Repeat, alert.CooldownHours)
+	if err := as.AlertRepository.Create(ctx, &alert, contact); err != nil {
+		log.Error("failed to create alert", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	log.Info("alert created", "id", alert.Id)
+	return &alert, nil
+}
+
+func (as *alertService) Fetch(ctx context.Context, contact models.Contacts) ([]models.Alert, error) {
+	op := alertsPlace + "Fetch"
+	log := as.Logger.AddOp(op)
+	alerts, err := as.AlertRepository.FetchByUser(ctx, contact)
+	if err != nil {
+		log.Error("failed to fetch alerts", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	return alerts, nil
+}
+
+func (as *alertService) Delete(ctx context.Context, contact models.Contacts, id int64) error {
+	op := alertsPlace + "Delete"
+	log := as.Logger.AddOp(op)
+	log.Info("deleting alert", "id", id)
+	if err := as.AlertRepository.Delete(ctx, id, contact); err != nil {
+		log.Error("failed to delete alert", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	log.Info("alert deleted", "id", id)
+	return nil
+}
+
+func (as *alertService) Evaluate(ctx context.Context, iphones []models.IPhone) ([]models.FiredAlert, error) {
+	op := alertsPlace + "Evaluate"
+	log := as.Logger.AddOp(op)
+	alerts, err := as.AlertRepository.FetchAll(ctx)
+	if err != nil {
+		log.Error("failed to fetch alerts", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	window := 0
+	for _, alert := range alerts {
+		window = max(window, alert.WindowHours)
+	}
+	now := time.Now()
+	history := map[string][]models.PriceRecord{}
+	checksOf := func(iphone models.IPhone) []models.PriceRecord {
+		if checks, ok := history[iphone.Id]; ok {
+			return checks
+		}
+		checks := []models.PriceRecord{}
+		records, err := as.PriceHistoryRepository.FetchByRange(ctx, iphone.Id, now.Add(-time.Duration(window)*time.Hour), now)
+		if err != nil {
+			log.Error("failed to fetch price history", "id", iphone.Id, logger.Err(err))
+		} else {
+			checks = previousChecks(checkPrices(records), iphone.CheckedAt)
+		}
+		history[iphone.Id] = checks
+		return checks
+	}
+	fired := []models.FiredAlert{}
+	for _, alert := range alerts {
+		if alert.Paused {
+			continue
+		}
+		matched := []models.FiredAlert{}
+		for _, iphone := range iphones {
+			if !alertMatches(alert, iphone) {
+				continue
+			}
+			var checks []models.PriceRecord
+			if alert.WindowHours > 0 {
+				checks = checksOf(iphone)
+			}
+			if reason, ok := alertReason(alert, iphone, checks, now); ok {
+				matched = append(matched, models.FiredAlert{IPhone: iphone, Reason: reason})
+			}
+		}
+		fire, state := alertState(alert, len(matched) > 0, now)
+		if !fire {
+			if state != alert.Fired {
+				if err := as.AlertRepository.UpdateState(ctx, alert.Id, state, alert.LastFiredAt); err != nil {
+					log.Error("failed to update alert state", "id", alert.Id, logger.Err(err))
+				}
+			}
+			continue
+		}
+		// the fired state is stored by MarkFired once the notifications are enqueued.
+		alert.LastFiredAt = &now
+		alert.Fired = state
+		for _, f := range matched {
+			f.Alert = alert
+			fired = append(fired, f)
+		}
+	}
+	log.Info("alerts evaluated", "alerts", len(alerts), "fired", len(fired))
+	return fired, nil
+}
+
+// MarkFired stores the state Evaluate gave to the fired alerts, call it after their notifications are enqueued
+// so a failed enqueue leaves the alerts to fire again on the next check.
+func (as *alertService) MarkFired(ctx context.Context, fired []models.FiredAlert) error {
+	op := alertsPlace + "MarkFired"
+	log := as.Logger.AddOp(op)
+	marked := map[int64]bool{}
+	for _, f := range fired {
+		if marked[f.Alert.Id] {
+			continue
+		}
+		marked[f.Alert.Id] = true
+		if err := as.AlertRepository.UpdateState(ctx, f.Alert.Id, f.Alert.Fired, f.Alert.LastFiredAt); err != nil {
+			log.Error("failed to update alert state", "id", f.Alert.Id, logger.Err(err))
+			return errs.NewAppError(op, err)
+		}
+	}
+	return nil
+}
+
+func alertDirection(kind, direction string) string {
+	switch kind {
+	case models.AlertKindDiscount:
+		return models.AlertDirectionAbove
+	case models.AlertKindDrop, models.AlertKindLow:
+		return models.AlertDirectionBelow
+	case models.AlertKindChange:
+		if direction == "" {
+			return models.AlertDirectionAny
+		}
+		return direction
+	}
+	if direction == "" || direction == models.AlertDirectionAny {
+		return models.AlertDirectionBelow
+	}
+	return direction
+}
+
+func alertWindow(kind string, window int) int {
+	switch kind {
+	case models.AlertKindDrop:
+		if window == 0 {
+			return defaultDropWindow
+		}
+		return window
+	case models.AlertKindLow:
+		if window == 0 {
+			return defaultLowWindow
+		}
+		return window
+	}
+	return 0
+}
+
+func alertRepeat(repeat string, cooldown int) (string, int) {
+	switch repeat {
+	case models.AlertRepeatOnce:
+		return repeat, 0
+	case models.AlertRepeatCooldown:
+		if cooldown == 0 {
+			return repeat, defaultCooldown
+		}
+		return repeat, cooldown
+	}
+	return models.AlertRepeatRearm, 0
+}
+
+// alertState decides whether the alert should be sent now and whether it stays fired afterwards.
+func alertState(alert models.Alert, triggered bool, now time.Time) (bool, bool) {
+	switch alert.Repeat {
+	case models.AlertRepeatOnce:
+		if alert.Fired {
+			return false, true
+		}
+		return triggered, triggered
+	case models.AlertRepeatCooldown:
+		if !triggered {
+			return false, false
+		}
+		if alert.LastFiredAt != nil && now.Sub(*alert.LastFiredAt) < time.Duration(alert.CooldownHours)*time.Hour {
+			return false, true
+		}
+		return true, true
+	}
+	return triggered && !alert.Fired, triggered
+}
+
+func alertMatches(alert models.Alert, iphone models.IPhone) bool {
+	if alert.IPhoneId != nil && *alert.IPhoneId != iphone.Id {
+		return false
+	}
+	if alert.Model != "" && !strings.EqualFold(alert.Model, iphone.Model) {
+		return false
+	}
+	if alert.ColorName != "" && !strings.EqualFold(alert.ColorName, iphone.ColorName) {
+		return false
+	}
+	if alert.Capacity != 0 && alert.Capacity != iphone.Capacity {
+		return false
+	}
+	return true
+}
+
+func alertReason(alert models.Alert, iphone models.IPhone, checks []models.PriceRecord, now time.Time) (string, bool) {
+	if iphone.Price <= 0 || iphone.Availability == models.AvailabilityOutOfStock {
+		return "", false
+	}
+	switch alert.Kind {
+	case models.AlertKindPrice:
+		if alert.Direction == models.AlertDirectionAbove {
+			if iphone.Price >= alert.Threshold {
+				return fmt.Sprintf("цена %.2f выше %.2f", iphone.Price, alert.Threshold), true
+			}
+			return "", false
+		}
+		if iphone.Price <= alert.Threshold {
+			return fmt.Sprintf("цена %.2f ниже %.2f", iphone.Price, alert.Threshold), true
+		}
+	case models.AlertKindDiscount:
+		if iphone.Discount > 0 && iphone.Discount >= alert.Threshold {
+			return fmt.Sprintf("скидка -%.0f%% от %.2f, ждали от %.0f%%", iphone.Discount, iphone.OldPrice, alert.Threshold), true
+		}
+	case models.AlertKindDrop:
+		from := now.Add(-time.Duration(alert.WindowHours) * time.Hour)
+		var top float64
+		for _, c := range checks {
+			if !c.CreatedAt.Before(from) && c.Price > top {
+				top = c.Price
+			}
+		}
+		if top <= 0 {
+			return "", false
+		}
+		drop := (top - iphone.Price) / top * 100
+		if drop >= alert.Threshold {
+			return fmt.Sprintf("цена упала на %.1f%% за %s: %.2f → %.2f", drop, models.FormatWindow(alert.WindowHours), top, iphone.Price), true
+		}
+	case models.AlertKindLow:
+		from := now.Add(-time.Duration(alert.WindowHours) * time.Hour)
+		low := math.Inf(1)
+		for _, c := range checks {
+			if !c.CreatedAt.Before(from) && c.Price < low {
+				low = c.Price
+			}
+		}
+		if !math.IsInf(low, 1) && iphone.Price < low {
+			return fmt.Sprintf("минимум за %s: %.2f, до этого было не ниже %.2f", models.FormatWindow(alert.WindowHours), iphone.Price, low), true
+		}
+	case models.AlertKindChange:
+		if iphone.Change == 0 || math.Abs(iphone.Change) < alert.Threshold {
+			return "", false
+		}
+		if (alert.Direction == models.AlertDirectionBelow && iphone.Change > 0) || (alert.Direction == models.AlertDirectionAbove && iphone.Change < 0) {
+			return "", false
+		}
+		return fmt.Sprintf("цена изменилась на %+.2f: %.2f → %.2f, порог %.2f", iphone.Change, iphone.Price-iphone.Change, iphone.Price, alert.Threshold), true
+	}
+	return "", false
+}
+
+func checkPrices(records []models.PriceRecord) []models.PriceRecord {
+	checks := []models.PriceRecord{}
+	for _, r := range records {
+		if !r.Available || r.Price <= 0 {
+			continue
+		}
+		last := len(checks) - 1
+		if last >= 0 && r.CreatedAt.Sub(checks[last].CreatedAt) < checkGap {
+			checks[last].Price = math.Min(checks[last].Price, r.Price)
+			continue
+		}
+		checks = append(checks, models.PriceRecord{IPhoneId: r.IPhoneId, Price: r.Price, Available: true, CreatedAt: r.CreatedAt})
+	}
+	return checks
+}
+
+func previousChecks(checks []models.PriceRecord, checkedAt *time.Time) []models.PriceRecord {
+	if len(checks) == 0 || checkedAt == nil {
+		return checks
+	}
+	last := checks[len(checks)-1]
+	if checkedAt.Sub(last.CreatedAt) < checkGap {
+		return checks[:len(checks)-1]
+	}
+	return checks
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	mock_repositories "iFall/internal/domain/repositories/mocks"
+	"iFall/internal/utils"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertService_Create(t *testing.T) {
+	type mockBehavior = func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository)
+	contact := models.Contacts{Email: "sanya@gmail.com"}
+	tests := []struct {
+		testName          string
+		alert             models.Alert
+		mockBehavior      mockBehavior
+		expectedDirection string
+		expectedError     error
+	}{
+		{
+			testName: "success default direction",
+			alert:    models.Alert{Kind: models.AlertKindPrice, Threshold: 2800},
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository) {
+				ar.EXPECT().Create(gomock.Any(), &models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800, Repeat: models.AlertRepeatRearm}, contact).Return(nil)
+			},
+			expectedDirection: models.AlertDirectionBelow,
+		},
+		{
+			testName: "success default cooldown",
+			alert:    models.Alert{Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatCooldown},
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository) {
+				ar.EXPECT().Create(gomock.Any(), &models.Alert{Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800, Repeat: models.AlertRepeatCooldown, CooldownHours: 24}, contact).Return(nil)
+			},
+			expectedDirection: models.AlertDirectionBelow,
+		},
+		{
+			testName: "success discount is always above",
+			alert:    models.Alert{Kind: models.AlertKindDiscount, Direction: models.AlertDirectionBelow, Threshold: 10},
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository) {
+				ar.EXPECT().Create(gomock.Any(), gomock.Any(), contact).Return(nil)
+			},
+			expectedDirection: models.AlertDirectionAbove,
+		},
+		{
+			testName: "iphone not found",
+			alert:    models.Alert{IPhoneId: utils.StrToPtr("iphone-nope-id"), Kind: models.AlertKindPrice, Threshold: 2800},
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository) {
+				ir.EXPECT().Get(gomock.Any(), "iphone-nope-id").Return(nil, errs.ErrNotFound("test-op"))
+			},
+			expectedError: errs.ErrNotFoundBase,
+		},
+		{
+			testName: "user not found",
+			alert:    models.Alert{IPhoneId: utils.StrToPtr("iphone-black-id"), Kind: models.AlertKindPrice, Direction: models.AlertDirectionAbove, Threshold: 3500},
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, ir *mock_repositories.MockIPhoneRepository) {
+				ir.EXPECT().Get(gomock.Any(), "iphone-black-id").Return(&models.IPhone{Id: "iphone-black-id"}, nil)
+				ar.EXPECT().Create(gomock.Any(), gomock.Any(), contact).Return(errs.ErrNotFound("test-op"))
+			},
+			expectedError: errs.ErrNotFoundBase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			alertMockRepo := mock_repositories.NewMockAlertRepository(c)
+			iphoneMockRepo := mock_repositories.NewMockIPhoneRepository(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			service := NewAlertService(alertMockRepo, iphoneMockRepo, mock_repositories.NewMockPriceHistoryRepository(c), logger)
+			tt.mockBehavior(alertMockRepo, iphoneMockRepo)
+			alert, err := service.Create(context.Background(), contact, tt.alert)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedDirection, alert.Direction)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAlertService_Evaluate(t *testing.T) {
+	type mockBehavior = func(ar *mock_repositories.MockAlertRepository, hr *mock_repositories.MockPriceHistoryRepository)
+	repoError := errors.New("repo error")
+	now := time.Now()
+	green := models.IPhone{Id: "iphone-green-id", Name: "iPhone 17 256GB Green", Model: "iPhone 17", ColorName: "green", Capacity: 256, Price: 2750, OldPrice: 3100, Discount: 11.3, Change: -200, CheckedAt: &now, Availability: models.AvailabilityInStock}
+	black := models.IPhone{Id: "iphone-black-id", Name: "iPhone 17 512GB Black", Model: "iPhone 17", ColorName: "black", Capacity: 512, Price: 3600, Availability: models.AvailabilityInStock}
+	gone := models.IPhone{Id: "iphone-white-id", Name: "iPhone 17 256GB White", Model: "iPhone 17", ColorName: "white", Capacity: 256, Price: 2000, Availability: models.AvailabilityOutOfStock}
+	tests := []struct {
+		testName        string
+		mockBehavior    mockBehavior
+		expectedReasons map[int64][]string
+		expectedError   error
+	}{
+		{
+			testName: "success filters and directions",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, hr *mock_repositories.MockPriceHistoryRepository) {
+				ar.EXPECT().FetchAll(gomock.Any()).Return([]models.Alert{
+					{Id: 1, Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2800},
+					{Id: 2, ColorName: "GREEN", Capacity: 256, Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 2700},
+					{Id: 3, IPhoneId: utils.StrToPtr("iphone-black-id"), Kind: models.AlertKindPrice, Direction: models.AlertDirectionAbove, Threshold: 3500},
+					{Id: 4, Model: "iPhone 17", Kind: models.AlertKindDiscount, Direction: models.AlertDirectionAbove, Threshold: 10},
+					{Id: 5, Model: "iPhone 17 Pro", Kind: models.AlertKindPrice, Direction: models.AlertDirectionBelow, Threshold: 5000},
+				}, nil)
+			},
+			expectedReasons: map[int64][]string{
+				1: {"цена 2750.00 ниже 2800.00"},
+				3: {"цена 3600.00 выше 3500.00"},
+				4: {"скидка -11% от 3100.00, ждали от 10%"},
+			},
+		},
+		{
+			testName: "success history rules",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, hr *mock_repositories.MockPriceHistoryRepository) {
+				ar.EXPECT().FetchAll(gomock.Any()).Return([]models.Alert{
+					{Id: 6, ColorName: "green", Kind: models.AlertKindDrop, Direction: models.AlertDirectionBelow, Threshold: 5, WindowHours: 24},
+					{Id: 7, ColorName: "green", Kind: models.AlertKindLow, Direction: models.AlertDirectionBelow, WindowHours: 30 * 24},
+					{Id: 8, ColorName: "green", Kind: models.AlertKindChange, Direction: models.AlertDirectionAny, Threshold: 100},
+					{Id: 9, ColorName: "green", Kind: models.AlertKindChange, Direction: models.AlertDirectionAbove, Threshold: 100},
+					{Id: 10, ColorName: "green", Kind: models.AlertKindDrop, Direction: models.AlertDirectionBelow, Threshold: 10, WindowHours: 24},
+				}, nil)
+				hr.EXPECT().FetchByRange(gomock.Any(), "iphone-green-id", gomock.Any(), gomock.Any()).Return([]models.PriceRecord{
+					{IPhoneId: "iphone-green-id", Source: "newton.by", Price: 2800, Available: true, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+					{IPhoneId: "iphone-green-id", Source: "newton.by", Price: 3000, Available: true, CreatedAt: now.Add(-20 * time.Hour)},
+					{IPhoneId: "iphone-green-id", Source: "shop.by", Price: 2950, Available: true, CreatedAt: now.Add(-20*time.Hour + time.Second)},
+					{IPhoneId: "iphone-green-id", Source: "shop.by", Price: 2500, Available: false, CreatedAt: now.Add(-19 * time.Hour)},
+					{IPhoneId: "iphone-green-id", Source: "newton.by", Price: 2750, Available: true, CreatedAt: now.Add(-time.Second)},
+				}, nil)
+			},
+			expectedReasons: map[int64][]string{
+				6: {"цена упала на 6.8% за 1 дн.: 2950.00 → 2750.00"},
+				7: {"минимум за 30 дн.: 2750.00, до этого было не ниже 2800.00"},
+				8: {"цена изменилась на -200.00: 2950.00 → 2750.00, порог 100.00"},
+			},
+		},
+		{
+			testName: "success repeat strategies",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, hr *mock_repositories.MockPriceHistoryRepository) {
+				recently := now.Add(-time.Hour)
+				longAgo := now.Add(-48 * time.Hour)
+				ar.EXPECT().FetchAll(gomock.Any()).Return([]models.Alert{
+					{Id: 11, Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatOnce, Fired: true, LastFiredAt: &longAgo},
+					{Id: 12, Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatRearm, Fired: true, LastFiredAt: &longAgo},
+					{Id: 13, Kind: models.AlertKindPrice, Threshold: 2000, Repeat: models.AlertRepeatRearm, Fired: true, LastFiredAt: &longAgo},
+					{Id: 14, Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatCooldown, CooldownHours: 24, Fired: true, LastFiredAt: &recently},
+					{Id: 15, Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatCooldown, CooldownHours: 24, Fired: true, LastFiredAt: &longAgo},
+					{Id: 16, Kind: models.AlertKindPrice, Threshold: 2000, Repeat: models.AlertRepeatOnce},
+					{Id: 17, Kind: models.AlertKindPrice, Threshold: 2800, Repeat: models.AlertRepeatRearm, Paused: true},
+					{Id: 18, Kind: models.AlertKindPrice, Threshold: 2000, Repeat: models.AlertRepeatRearm, Fired: true, Paused: true},
+				}, nil)
+				ar.EXPECT().UpdateState(gomock.Any(), int64(13), false, &longAgo).Return(nil)
+			},
+			expectedReasons: map[int64][]string{
+				15: {"цена 2750.00 ниже 2800.00"},
+			},
+		},
+		{
+			testName: "failed fetching alerts",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository, hr *mock_repositories.MockPriceHistoryRepository) {
+				ar.EXPECT().FetchAll(gomock.Any()).Return(nil, repoError)
+			},
+			expectedError: repoError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			alertMockRepo := mock_repositories.NewMockAlertRepository(c)
+			historyMockRepo := mock_repositories.NewMockPriceHistoryRepository(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			service := NewAlertService(alertMockRepo, mock_repositories.NewMockIPhoneRepository(c), historyMockRepo, logger)
+			tt.mockBehavior(alertMockRepo, historyMockRepo)
+			fired, err := service.Evaluate(context.Background(), []models.IPhone{green, black, gone})
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				reasons := map[int64][]string{}
+				for _, f := range fired {
+					reasons[f.Alert.Id] = append(reasons[f.Alert.Id], f.Reason)
+					assert.True(t, f.Alert.Fired)
+					assert.Equal(t, now.Truncate(time.Minute), f.Alert.LastFiredAt.Truncate(time.Minute))
+				}
+				assert.Equal(t, tt.expectedReasons, reasons)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAlertService_MarkFired(t *testing.T) {
+	now := time.Now()
+	repoError := errors.New("repo error")
+	iphone := models.IPhone{Id: "iphone-green-id"}
+	fired := []models.FiredAlert{
+		{Alert: models.Alert{Id: 1, Fired: true, LastFiredAt: &now}, IPhone: iphone},
+		{Alert: models.Alert{Id: 1, Fired: true, LastFiredAt: &now}, IPhone: models.IPhone{Id: "iphone-black-id"}},
+		{Alert: models.Alert{Id: 2, Fired: false, LastFiredAt: &now}, IPhone: iphone},
+	}
+	tests := []struct {
+		testName      string
+		mockBehavior  func(ar *mock_repositories.MockAlertRepository)
+		expectedError error
+	}{
+		{
+			testName: "success once per alert",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository) {
+				ar.EXPECT().UpdateState(gomock.Any(), int64(1), true, &now).Return(nil)
+				ar.EXPECT().UpdateState(gomock.Any(), int64(2), false, &now).Return(nil)
+			},
+		},
+		{
+			testName: "failed updating state",
+			mockBehavior: func(ar *mock_repositories.MockAlertRepository) {
+				ar.EXPECT().UpdateState(gomock.Any(), int64(1), true, &now).Return(repoError)
+			},
+			expectedError: repoError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			alertMockRepo := mock_repositories.NewMockAlertRepository(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			service := NewAlertService(alertMockRepo, mock_repositories.NewMockIPhoneRepository(c), mock_repositories.NewMockPriceHistoryRepository(c), logger)
+			tt.mockBehavior(alertMockRepo)
+			err := service.MarkFired(context.Background(), fired)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
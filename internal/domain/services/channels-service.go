@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/repositories"
+	"iFall/internal/notifier"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+)
+
+//go:generate mockgen -source=channels-service.go -destination=mocks/channels-service-mock.go
+type ChannelService interface {
+	Channels() []string
+	ValidTarget(channel, target string) bool
+	Fetch(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error)
+	Set(ctx context.Context, contact models.Contacts, channel models.UserChannel) error
+}
+
+type channelService struct {
+	ChannelRepository repositories.ChannelRepository
+	Notifiers         *notifier.Registry
+	Logger            *logger.Logger
+}
+
+func NewChannelService(cr repositories.ChannelRepository, nr *notifier.Registry, l *logger.Logger) ChannelService {
+	return &channelService{
+		ChannelRepository: cr,
+		Notifiers:         nr,
+		Logger:            l,
+	}
+}
+
+const channelsPlace = "channelService."
+
+func (cs *channelService) Channels() []string {
+	return cs.Notifiers.Channels()
+}
+
+func (cs *channelService) ValidTarget(channel, target string) bool {
+	return cs.Notifiers.ValidTarget(channel, target)
+}
+
+func (cs *channelService) Fetch(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error) {
+	op := channelsPlace + "Fetch"
+	log := cs.Logger.AddOp(op)
+	channels, err := cs.ChannelRepository.FetchByUser(ctx, contact)
+	if err != nil {
+		log.Error("failed to fetch channels", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	return channels, nil
+}
+
+func (cs *channelService) Set(ctx context.Context, contact models.Contacts, channel models.UserChannel) error {
+	op := channelsPlace + "Set"
+	log := cs.Logger.AddOp(op)
+	log.Info("setting channel", "channel", channel.Channel, "enabled", channel.Enabled)
+	if channel.Channel == models.ChannelEmail || channel.Channel == models.ChannelTelegram {
+		channel.Target = ""
+	}
+	if err := cs.ChannelRepository.Set(ctx, channel, contact); err != nil {
+		log.Error("failed to set channel", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	return nil
+}
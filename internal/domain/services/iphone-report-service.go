@@ -2,35 +2,42 @@ package services
 
 import (
 	"context"
-	"iFall/internal/bot"
 	"iFall/internal/config"
 	"iFall/internal/domain/models"
 	"iFall/internal/domain/repositories"
-	"iFall/internal/email"
+	"iFall/internal/notifier"
 	"iFall/pkg/errs"
 	"iFall/pkg/logger"
-	"sync"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
 )
 
 type IphoneReportService interface {
 	SendIPhonesInfo(emailSupp bool, iphones []models.IPhone) error
+	SendBackInStock(emailSupp bool, iphones []models.IPhone) error
 }
 
 type iPhoneReportService struct {
-	UserRepository repositories.UserRepository
-	IPonesConfig   config.IPhonesConfig
-	EmailSender    email.EmailSender
-	Bot            bot.TelegramBot
-	Logger         *logger.Logger
+	ChannelRepository           repositories.ChannelRepository
+	StockSubscriptionRepository repositories.StockSubscriptionRepository
+	AlertService                AlertService
+	OutboxService               OutboxService
+	Notifiers                   *notifier.Registry
+	IPonesConfig                config.IPhonesConfig
+	Logger                      *logger.Logger
 }
 
-func NewIPhoneReportService(ur repositories.UserRepository, l *logger.Logger, b bot.TelegramBot, es email.EmailSender, cfg config.IPhonesConfig) IphoneReportService {
+func NewIPhoneReportService(cr repositories.ChannelRepository, sr repositories.StockSubscriptionRepository, as AlertService, obs OutboxService, nr *notifier.Registry, l *logger.Logger, cfg config.IPhonesConfig) IphoneReportService {
 	return &iPhoneReportService{
-		UserRepository: ur,
-		EmailSender:    es,
-		Bot:            b,
-		IPonesConfig:   cfg,
-		Logger:         l,
+		ChannelRepository:           cr,
+		StockSubscriptionRepository: sr,
+		AlertService:                as,
+		OutboxService:               obs,
+		Notifiers:                   nr,
+		IPonesConfig:                cfg,
+		Logger:                      l,
 	}
 }
 
@@ -40,73 +47,164 @@ func (irs *iPhoneReportService) SendIPhonesInfo(emailSupp bool, iphones []models
 	log.Info("sending iphones info")
 	ctx, cancel := context.WithTimeout(context.Background(), irs.IPonesConfig.Timeout)
 	defer cancel()
-	contacts, err := irs.UserRepository.FetchContacts(ctx)
+	channels, err := irs.ChannelRepository.FetchEnabled(ctx)
 	if err != nil {
 		return errs.NewAppError(op, err)
 	}
-	emails := []string{}
-	datas := []bot.DataToSend{}
-	if len(contacts) > 0 {
-		for _, c := range contacts {
-			if emailSupp {
-				emails = append(emails, c.Email)
-			}
-			if c.ChatId != nil {
-				data := bot.DataToSend{
-					Price:  c.DesiredPrice,
-					ChatId: *c.ChatId,
-				}
-				datas = append(datas, data)
-			}
-		}
-	} else {
+	channels = append(channels, irs.Notifiers.Global()...)
+	if len(channels) == 0 {
 		return nil
 	}
+	fired, err := irs.AlertService.Evaluate(ctx, iphones)
+	if err != nil {
+		log.Error("failed to evaluate alerts", logger.Err(err))
+	}
+	userAlerts := map[string][]models.FiredAlert{}
+	for _, f := range fired {
+		key := contactKey(f.Alert.Contacts)
+		userAlerts[key] = append(userAlerts[key], f)
+	}
 
-	errlen := len(datas) + 1
-	if emailSupp {
-		errlen = len(contacts) + 1
+	report := notifier.Notification{Kind: notifier.KindReport, IPhones: iphones}
+	reports := map[string]notifier.Message{}
+	messages := []models.OutboxMessage{}
+	for _, channel := range channels {
+		n, recipient, ok := irs.recipient(emailSupp, channel)
+		if !ok {
+			continue
+		}
+		msg, ok := reports[channel.Channel]
+		if !ok {
+			if msg, err = n.Render(report); err != nil {
+				log.Error("failed to render report", "channel", channel.Channel, logger.Err(err))
+				return errs.NewAppError(op, err)
+			}
+			reports[channel.Channel] = msg
+		}
+		messages = append(messages, outboxMessage(channel.Channel, recipient, msg))
+		alerts := userAlerts[contactKey(channel.Contacts)]
+		if channel.Global {
+			alerts = fired
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+		msg, err := n.Render(notifier.Notification{Kind: notifier.KindAlerts, IPhones: iphones, Alerts: alerts})
+		if err != nil {
+			log.Error("failed to render alerts", "channel", channel.Channel, logger.Err(err))
+			return errs.NewAppError(op, err)
+		}
+		messages = append(messages, outboxMessage(channel.Channel, recipient, msg))
 	}
+	if err := irs.OutboxService.Enqueue(ctx, messages); err != nil {
+		log.Error("failed to enqueue iphones info", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	if len(fired) > 0 {
+		if err := irs.AlertService.MarkFired(ctx, fired); err != nil {
+			log.Error("failed to mark fired alerts", logger.Err(err))
+		}
+	}
+
+	log.Info("iphones info enqueued", "messages", len(messages))
+	return nil
+}
 
-	errChan := make(chan error, errlen)
-	var wg sync.WaitGroup
-	if emailSupp {
-		if len(emails) > 0 {
-			wg.Add(1)
-			log.Info("sending on emails")
-			emailCtx, cancel := context.WithTimeout(context.Background(), irs.IPonesConfig.Timeout)
-			defer cancel()
-			subject := "цена говнофона семнадцатого 17"
-			content, err := email.BuildEmailLetter(iphones)
+func (irs *iPhoneReportService) SendBackInStock(emailSupp bool, iphones []models.IPhone) error {
+	op := "iPhoneReportService.SendBackInStock"
+	log := irs.Logger.AddOp(op)
+	var lastErr error
+	var channels []models.UserChannel
+	for _, iphone := range iphones {
+		if !iphone.BackInStock {
+			continue
+		}
+		if channels == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), irs.IPonesConfig.Timeout)
+			fetched, err := irs.ChannelRepository.FetchEnabled(ctx)
+			cancel()
 			if err != nil {
-				errChan <- err
+				return errs.NewAppError(op, err)
 			}
-			go func() {
-				defer wg.Done()
-				if err := irs.EmailSender.SendMessage(emailCtx, subject, []byte(content), emails, nil); err != nil {
-					errChan <- err
-				}
-			}()
+			channels = fetched
+		}
+		log.Info("sending back in stock notifications", "id", iphone.Id)
+		if err := irs.sendBackInStock(emailSupp, iphone, channels); err != nil {
+			log.Error("failed to send back in stock notifications", "id", iphone.Id, logger.Err(err))
+			lastErr = err
 		}
 	}
-	if len(datas) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("sending on telegrams")
-			if err := irs.Bot.SendIPhonesInfo(datas, iphones); err != nil {
-				errChan <- err
-			}
-		}()
+	if lastErr != nil {
+		return errs.NewAppError(op, lastErr)
+	}
+	return nil
+}
+
+func (irs *iPhoneReportService) sendBackInStock(emailSupp bool, iphone models.IPhone, channels []models.UserChannel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), irs.IPonesConfig.Timeout)
+	defer cancel()
+	users, err := irs.StockSubscriptionRepository.FetchSubscribers(ctx, iphone.Id)
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	close(errChan)
+	if len(users) == 0 {
+		return nil
+	}
+	subscribers := map[string]uuid.UUID{}
+	for _, u := range users {
+		subscribers[contactKey(u.Contacts)] = u.Id
+	}
+	notification := notifier.Notification{Kind: notifier.KindBackInStock, IPhones: []models.IPhone{iphone}}
+	messages := []models.OutboxMessage{}
+	notified := []uuid.UUID{}
+	for _, channel := range channels {
+		userId, ok := subscribers[contactKey(channel.Contacts)]
+		if !ok {
+			continue
+		}
+		n, recipient, ok := irs.recipient(emailSupp, channel)
+		if !ok {
+			continue
+		}
+		msg, err := n.Render(notification)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, outboxMessage(channel.Channel, recipient, msg))
+		if !slices.Contains(notified, userId) {
+			notified = append(notified, userId)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	if err := irs.OutboxService.Enqueue(ctx, messages); err != nil {
+		return err
+	}
+	// subscribers without a deliverable channel keep the subscription until one is enabled
+	return irs.StockSubscriptionRepository.DeleteByIPhoneAndUsers(ctx, iphone.Id, notified)
+}
 
-	for err := range errChan {
-		log.Error("failed to send iphones info", logger.Err(err))
-		return errs.NewAppError(op, err)
+func (irs *iPhoneReportService) recipient(emailSupp bool, channel models.UserChannel) (notifier.Notifier, string, bool) {
+	if channel.Channel == models.ChannelEmail && !emailSupp {
+		return nil, "", false
 	}
+	n, ok := irs.Notifiers.Get(channel.Channel)
+	if !ok {
+		irs.Logger.Error("no notifier for channel", "channel", channel.Channel)
+		return nil, "", false
+	}
+	recipient, ok := n.Recipient(channel)
+	return n, recipient, ok
+}
 
-	log.Info("iphones info sended")
-	return nil
+func contactKey(c models.Contacts) string {
+	if c.ChatId != nil {
+		return "chat:" + strconv.FormatInt(*c.ChatId, 10)
+	}
+	return "email:" + c.Email
+}
+
+func outboxMessage(channel, recipient string, msg notifier.Message) models.OutboxMessage {
+	return models.OutboxMessage{Channel: channel, Recipient: recipient, Subject: msg.Subject, Body: msg.Body}
 }
@@ -2,26 +2,76 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	mock_bot "iFall/internal/bot/mocks"
 	"iFall/internal/config"
 	"iFall/internal/domain/models"
 	mock_repositories "iFall/internal/domain/repositories/mocks"
+	mock_services "iFall/internal/domain/services/mocks"
 	mock_email "iFall/internal/email/mocks"
+	"iFall/internal/notifier"
+	mock_notifier "iFall/internal/notifier/mocks"
 	"iFall/internal/utils"
+	"net/http"
+	"strings"
 
 	"iFall/pkg/logger"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+type outboxRecipients []string
+
+func (r outboxRecipients) Matches(x any) bool {
+	messages, ok := x.([]models.OutboxMessage)
+	if !ok || len(messages) != len(r) {
+		return false
+	}
+	for i, m := range messages {
+		if m.Channel+":"+m.Recipient != r[i] || m.Body == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r outboxRecipients) String() string {
+	return fmt.Sprintf("outbox messages for %s", strings.Join(r, ", "))
+}
+
+func testNotifiers(c *gomock.Controller, extra ...notifier.Notifier) *notifier.Registry {
+	notifiers := notifier.NewRegistry(
+		notifier.NewEmailNotifier(mock_email.NewMockEmailSender(c)),
+		notifier.NewTelegramNotifier(mock_bot.NewMockTelegramBot(c)),
+	)
+	for _, n := range extra {
+		notifiers.Register(n)
+	}
+	return notifiers
+}
+
 func TestIphoneReportService_Test(t *testing.T) {
-	type mockBehavior = func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot)
-	sendingError := errors.New("sending error")
+	type mockBehavior = func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier)
+	enqueueError := errors.New("enqueue error")
+	iphones := []models.IPhone{
+		{Id: "iphone-black-id", Name: "iphone-black-name", Price: 900.0, Change: 0.0, Color: "black"},
+		{Id: "iphone-white-id", Name: "iphone-white-name", Price: 920.0, Change: 20.0, Color: "white"},
+		{Id: "iphone-blue-id", Name: "iphone-blue-name", Price: 1000, Change: -100, Color: "blue"},
+	}
+	contacts := []models.Contacts{
+		{Telegram: utils.StrToPtr("tg1"), Email: "kiremail@gmail.com", ChatId: utils.Int64ToPtr(111)},
+		{Telegram: nil, Email: "gusemail@gmail.com", ChatId: nil},
+	}
+	channels := []models.UserChannel{
+		{Channel: models.ChannelEmail, Enabled: true, Contacts: contacts[0]},
+		{Channel: models.ChannelTelegram, Enabled: true, Contacts: contacts[0]},
+		{Channel: models.ChannelEmail, Enabled: true, Contacts: contacts[1]},
+	}
 	type ttData struct {
-		iphones       []models.IPhone
 		emailSupp     bool
 		expectedError error
 	}
@@ -32,415 +82,207 @@ func TestIphoneReportService_Test(t *testing.T) {
 	}{
 		{
 			testName: "success reporting with email sup",
-			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
-				expectedError: nil,
-				emailSupp:     true,
-			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: utils.StrToPtr("tg1"),
-						Email:    "kiremail@gmail.com",
-						ChatId:   utils.Int64ToPtr(000000),
-					},
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
-				}, nil)
-				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(nil)
-				bm.EXPECT().SendIPhonesInfo(gomock.Any(), []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				}).Return(nil)
+			ttData:   ttData{emailSupp: true},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{}, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"email:kiremail@gmail.com", "telegram:111", "email:gusemail@gmail.com"}).Return(nil)
 			},
 		},
 		{
 			testName: "success reporting without email sup",
-			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
-				expectedError: nil,
-				emailSupp:     false,
+			ttData:   ttData{emailSupp: false},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{}, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111"}).Return(nil)
 			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: utils.StrToPtr("tg1"),
-						Email:    "kiremail@gmail.com",
-						ChatId:   utils.Int64ToPtr(000000),
-					},
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
+		},
+		{
+			testName: "zero contacts",
+			ttData:   ttData{emailSupp: true},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return([]models.UserChannel{}, nil)
+			},
+		},
+		{
+			testName: "success reporting when alerts evaluation failed",
+			ttData:   ttData{emailSupp: false},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return(nil, errors.New("evaluate error"))
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111"}).Return(nil)
+			},
+		},
+		{
+			testName: "success reporting fired alerts",
+			ttData:   ttData{emailSupp: true},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				fired := []models.FiredAlert{
+					{Alert: models.Alert{Id: 1, Contacts: contacts[0]}, IPhone: iphones[0], Reason: "цена 900.00 ниже 1000.00"},
+					{Alert: models.Alert{Id: 2, Contacts: contacts[1]}, IPhone: iphones[1], Reason: "цена 920.00 ниже 1000.00"},
+				}
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return(fired, nil)
+				gomock.InOrder(
+					om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{
+						"email:kiremail@gmail.com",
+						"email:kiremail@gmail.com",
+						"telegram:111",
+						"telegram:111",
+						"email:gusemail@gmail.com",
+						"email:gusemail@gmail.com",
+					}).Return(nil),
+					am.EXPECT().MarkFired(gomock.Any(), fired).Return(nil),
+				)
+			},
+		},
+		{
+			testName: "failed to enqueue fired alerts",
+			ttData:   ttData{emailSupp: false, expectedError: enqueueError},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{
+					{Alert: models.Alert{Id: 1, Contacts: contacts[0]}, IPhone: iphones[0], Reason: "цена 900.00 ниже 1000.00"},
 				}, nil)
-				bm.EXPECT().SendIPhonesInfo(gomock.Any(), []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				}).Return(nil)
+				om.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(enqueueError)
 			},
 		},
 		{
-			testName: "zero contacts",
+			testName: "success reporting to registered custom channel",
+			ttData:   ttData{emailSupp: false},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				pager := models.UserChannel{Channel: "pager", Target: "pager-1", Enabled: true, Contacts: contacts[1]}
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(append(channels, pager, models.UserChannel{Channel: "pigeon", Target: "roof", Enabled: true}), nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{}, nil)
+				pm.EXPECT().Recipient(pager).Return("pager-1", true)
+				pm.EXPECT().Render(notifier.Notification{Kind: notifier.KindReport, IPhones: iphones}).Return(notifier.Message{Body: "beep"}, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111", "pager:pager-1"}).Return(nil)
+			},
+		},
+		{
+			testName: "failed to enqueue",
+			ttData:   ttData{emailSupp: true, expectedError: enqueueError},
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, am *mock_services.MockAlertService, om *mock_services.MockOutboxService, pm *mock_notifier.MockNotifier) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				am.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{}, nil)
+				om.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(enqueueError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			channelMockRepo := mock_repositories.NewMockChannelRepository(c)
+			alertMockService := mock_services.NewMockAlertService(c)
+			outboxMockService := mock_services.NewMockOutboxService(c)
+			pagerMock := mock_notifier.NewMockNotifier(c)
+			pagerMock.EXPECT().Channel().Return("pager").AnyTimes()
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			notifiers := testNotifiers(c, pagerMock)
+			service := NewIPhoneReportService(channelMockRepo, mock_repositories.NewMockStockSubscriptionRepository(c), alertMockService, outboxMockService, notifiers, logger, config.IPhonesConfig{Timeout: time.Second})
+			tt.mockBehavior(channelMockRepo, alertMockService, outboxMockService, pagerMock)
+			err := service.SendIPhonesInfo(tt.ttData.emailSupp, iphones)
+			if tt.ttData.expectedError != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.ttData.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIphoneReportService_SendBackInStock(t *testing.T) {
+	type mockBehavior = func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService)
+	enqueueError := errors.New("enqueue error")
+	backInStock := models.IPhone{Id: "iphone-black-id", Name: "iphone-black-name", Price: 900.0, Availability: models.AvailabilityInStock, BackInStock: true}
+	subscribers := []models.User{
+		{Id: uuid.MustParse("5b1c7a0e-3d4f-4c8a-9e2b-1a6f0d7c8e91"), Contacts: models.Contacts{Email: "kiremail@gmail.com", ChatId: utils.Int64ToPtr(111)}},
+		{Id: uuid.MustParse("8e4d2c1b-7a6f-4e3d-b5c9-0f1e2d3c4b5a"), Contacts: models.Contacts{Email: "gusemail@gmail.com"}},
+	}
+	channels := []models.UserChannel{
+		{Channel: models.ChannelTelegram, Enabled: true, Contacts: subscribers[0].Contacts},
+		{Channel: models.ChannelEmail, Enabled: true, Contacts: subscribers[1].Contacts},
+		{Channel: models.ChannelEmail, Enabled: true, Contacts: models.Contacts{Email: "nosub@gmail.com"}},
+	}
+	type ttData struct {
+		iphones       []models.IPhone
+		emailSupp     bool
+		expectedError error
+	}
+	tests := []struct {
+		testName     string
+		ttData       ttData
+		mockBehavior mockBehavior
+	}{
+		{
+			testName: "success notifying telegram and email",
 			ttData: ttData{
 				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
+					backInStock,
+					{Id: "iphone-white-id", Name: "iphone-white-name", Price: 920.0, Availability: models.AvailabilityInStock},
 				},
-				expectedError: nil,
 				emailSupp:     true,
+				expectedError: nil,
 			},
-
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{}, nil)
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				sm.EXPECT().FetchSubscribers(gomock.Any(), "iphone-black-id").Return(subscribers, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111", "email:gusemail@gmail.com"}).Return(nil)
+				sm.EXPECT().DeleteByIPhoneAndUsers(gomock.Any(), "iphone-black-id", []uuid.UUID{subscribers[0].Id, subscribers[1].Id}).Return(nil)
 			},
 		},
 		{
-			testName: "only emails",
+			testName: "success keeping subscription of skipped email subscriber",
 			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
-				emailSupp:     true,
+				iphones:       []models.IPhone{backInStock},
+				emailSupp:     false,
 				expectedError: nil,
 			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
-				}, nil)
-				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(nil)
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				sm.EXPECT().FetchSubscribers(gomock.Any(), "iphone-black-id").Return(subscribers, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111"}).Return(nil)
+				sm.EXPECT().DeleteByIPhoneAndUsers(gomock.Any(), "iphone-black-id", []uuid.UUID{subscribers[0].Id}).Return(nil)
 			},
 		},
 		{
-			testName: "failed to send",
+			testName: "success without deliverable channels",
 			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
-				emailSupp:     true,
-				expectedError: sendingError,
+				iphones:       []models.IPhone{backInStock},
+				emailSupp:     false,
+				expectedError: nil,
 			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: utils.StrToPtr("tg1"),
-						Email:    "kiremail@gmail.com",
-						ChatId:   utils.Int64ToPtr(000000),
-					},
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
-				}, nil)
-				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(sendingError)
-				bm.EXPECT().SendIPhonesInfo(gomock.Any(), []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				}).Return(sendingError)
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				sm.EXPECT().FetchSubscribers(gomock.Any(), "iphone-black-id").Return(subscribers[1:], nil)
 			},
 		},
 		{
-			testName: "failed to send emails",
+			testName: "success without subscribers",
 			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
+				iphones:       []models.IPhone{backInStock},
 				emailSupp:     true,
-				expectedError: sendingError,
+				expectedError: nil,
 			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: utils.StrToPtr("tg1"),
-						Email:    "kiremail@gmail.com",
-						ChatId:   utils.Int64ToPtr(000000),
-					},
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
-				}, nil)
-				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(sendingError)
-				bm.EXPECT().SendIPhonesInfo(gomock.Any(), []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				}).Return(nil)
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				sm.EXPECT().FetchSubscribers(gomock.Any(), "iphone-black-id").Return([]models.User{}, nil)
 			},
 		},
 		{
-			testName: "failed to send telegram",
+			testName: "failed enqueue keeps subscriptions",
 			ttData: ttData{
-				iphones: []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				},
-				emailSupp:     true,
-				expectedError: sendingError,
+				iphones:       []models.IPhone{backInStock},
+				emailSupp:     false,
+				expectedError: enqueueError,
 			},
-			mockBehavior: func(um *mock_repositories.MockUserRepository, em *mock_email.MockEmailSender, bm *mock_bot.MockTelegramBot) {
-				um.EXPECT().FetchContacts(gomock.Any()).Return([]models.Contacts{
-					{
-						Telegram: utils.StrToPtr("tg1"),
-						Email:    "kiremail@gmail.com",
-						ChatId:   utils.Int64ToPtr(000000),
-					},
-					{
-						Telegram: nil,
-						Email:    "gusemail$gmail.com",
-						ChatId:   nil,
-					},
-				}, nil)
-				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(nil)
-				bm.EXPECT().SendIPhonesInfo(gomock.Any(), []models.IPhone{
-					{
-						Id:     "iphone-black-id",
-						Name:   "iphone-black-name",
-						Price:  900.0,
-						Change: 0.0,
-						Color:  "black",
-					},
-					{
-						Id:     "iphone-white-id",
-						Name:   "iphone-white-name",
-						Price:  920.0,
-						Change: 20.0,
-						Color:  "white",
-					},
-					{
-						Id:     "iphone-blue-id",
-						Name:   "iphone-blue-name",
-						Price:  1000,
-						Change: 100,
-						Color:  "blue",
-					},
-				}).Return(sendingError)
+			mockBehavior: func(cm *mock_repositories.MockChannelRepository, sm *mock_repositories.MockStockSubscriptionRepository, om *mock_services.MockOutboxService) {
+				cm.EXPECT().FetchEnabled(gomock.Any()).Return(channels, nil)
+				sm.EXPECT().FetchSubscribers(gomock.Any(), "iphone-black-id").Return(subscribers, nil)
+				om.EXPECT().Enqueue(gomock.Any(), outboxRecipients{"telegram:111"}).Return(enqueueError)
 			},
 		},
 	}
@@ -448,13 +290,13 @@ func TestIphoneReportService_Test(t *testing.T) {
 		t.Run(tt.testName, func(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
-			userMockRepo := mock_repositories.NewMockUserRepository(c)
-			emailMock := mock_email.NewMockEmailSender(c)
-			botMock := mock_bot.NewMockTelegramBot(c)
+			channelMockRepo := mock_repositories.NewMockChannelRepository(c)
+			stockMockRepo := mock_repositories.NewMockStockSubscriptionRepository(c)
+			outboxMockService := mock_services.NewMockOutboxService(c)
 			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
-			service := NewIPhoneReportService(userMockRepo, logger, botMock, emailMock, config.IPhonesConfig{Timeout: time.Second})
-			tt.mockBehavior(userMockRepo, emailMock, botMock)
-			err := service.SendIPhonesInfo(tt.ttData.emailSupp, tt.ttData.iphones)
+			service := NewIPhoneReportService(channelMockRepo, stockMockRepo, mock_services.NewMockAlertService(c), outboxMockService, testNotifiers(c), logger, config.IPhonesConfig{Timeout: time.Second})
+			tt.mockBehavior(channelMockRepo, stockMockRepo, outboxMockService)
+			err := service.SendBackInStock(tt.ttData.emailSupp, tt.ttData.iphones)
 			if tt.ttData.expectedError != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.ttData.expectedError)
@@ -464,3 +306,29 @@ func TestIphoneReportService_Test(t *testing.T) {
 		})
 	}
 }
+
+func TestIphoneReportService_GlobalWebhooks(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	channelMockRepo := mock_repositories.NewMockChannelRepository(c)
+	alertMockService := mock_services.NewMockAlertService(c)
+	outboxMockService := mock_services.NewMockOutboxService(c)
+	logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+	webhook := notifier.NewWebhookNotifier(http.DefaultClient, config.WebhookConfig{Secret: "test-secret", Urls: []string{"https://hooks.local/ifall"}})
+	service := NewIPhoneReportService(channelMockRepo, mock_repositories.NewMockStockSubscriptionRepository(c), alertMockService, outboxMockService, testNotifiers(c, webhook), logger, config.IPhonesConfig{Timeout: time.Second})
+	iphones := []models.IPhone{{Id: "iphone-black-id", Name: "iphone-black-name", Price: 900.0}}
+	sanya := models.Contacts{Email: "kiremail@gmail.com", ChatId: utils.Int64ToPtr(111)}
+
+	channelMockRepo.EXPECT().FetchEnabled(gomock.Any()).Return([]models.UserChannel{{Channel: models.ChannelTelegram, Enabled: true, Contacts: sanya}}, nil)
+	alertMockService.EXPECT().Evaluate(gomock.Any(), iphones).Return([]models.FiredAlert{
+		{Alert: models.Alert{Id: 1, Contacts: sanya}, IPhone: iphones[0], Reason: "цена 900.00 ниже 1000.00"},
+	}, nil)
+	outboxMockService.EXPECT().Enqueue(gomock.Any(), outboxRecipients{
+		"telegram:111",
+		"telegram:111",
+		"webhook:https://hooks.local/ifall",
+		"webhook:https://hooks.local/ifall",
+	}).Return(nil)
+	alertMockService.EXPECT().MarkFired(gomock.Any(), gomock.Any()).Return(nil)
+	assert.NoError(t, service.SendIPhonesInfo(false, iphones))
+}
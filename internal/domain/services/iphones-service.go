@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"iFall/internal/client"
 	"iFall/internal/config"
 	"iFall/internal/domain/models"
@@ -17,26 +18,36 @@ import (
 //go:generate mockgen -source=iphones-service.go -destination=mocks/iphones-service-mock.go
 type IPhoneService interface {
 	Get(ctx context.Context, id string) (*models.IPhone, error)
+	Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error)
+	History(ctx context.Context, id string, from, to time.Time, bucket string) (*models.PriceHistory, error)
 	UpdateAll() ([]models.IPhone, error)
 	Update(ctx context.Context, id string) (*models.IPhone, error)
+	Subscribe(ctx context.Context, id string, contact models.Contacts) error
+	Unsubscribe(ctx context.Context, id string, contact models.Contacts) error
 }
 
 type iPhoneService struct {
-	IPhoneRepository repositories.IPhoneRepository
-	ApiClient        client.ApiClient
-	IPhonesConfig    config.IPhonesConfig
-	EmailSendler     email.EmailSender
-	Logger           *logger.Logger
-	Mutex            sync.Mutex
+	IPhoneRepository            repositories.IPhoneRepository
+	PriceHistoryRepository      repositories.PriceHistoryRepository
+	OfferRepository             repositories.OfferRepository
+	StockSubscriptionRepository repositories.StockSubscriptionRepository
+	ApiClient                   client.ApiClient
+	IPhonesConfig               config.IPhonesConfig
+	EmailSendler                email.EmailSender
+	Logger                      *logger.Logger
+	Mutex                       sync.Mutex
 }
 
-func NewIPhoneService(ir repositories.IPhoneRepository, ac client.ApiClient, l *logger.Logger, es email.EmailSender, cfg config.IPhonesConfig) IPhoneService {
+func NewIPhoneService(ir repositories.IPhoneRepository, phr repositories.PriceHistoryRepository, or repositories.OfferRepository, sr repositories.StockSubscriptionRepository, ac client.ApiClient, l *logger.Logger, es email.EmailSender, cfg config.IPhonesConfig) IPhoneService {
 	return &iPhoneService{
-		IPhoneRepository: ir,
-		ApiClient:        ac,
-		Logger:           l,
-		IPhonesConfig:    cfg,
-		EmailSendler:     es,
+		IPhoneRepository:            ir,
+		PriceHistoryRepository:      phr,
+		OfferRepository:             or,
+		StockSubscriptionRepository: sr,
+		ApiClient:                   ac,
+		Logger:                      l,
+		IPhonesConfig:               cfg,
+		EmailSendler:                es,
 	}
 }
 
@@ -51,14 +62,266 @@ func (is *iPhoneService) Get(ctx context.Context, id string) (*models.IPhone, er
 		log.Error("failed to receive iphone", logger.Err(err))
 		return nil, errs.NewAppError(op, err)
 	}
+	offers, err := is.OfferRepository.FetchByIPhone(ctx, id)
+	if err != nil {
+		log.Error("failed to receive offers", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	iphone.Offers = offers
+	iphone.BestOffer, iphone.Spread = bestOffer(offers)
 	log.Info("iphone received", "id", iphone.Id)
 	return iphone, nil
 }
 
+func (is *iPhoneService) Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error) {
+	op := place + "Fetch"
+	log := is.Logger.AddOp(op)
+	log.Info("iphones receiving")
+	iphones, total, err := is.IPhoneRepository.Fetch(ctx, filter)
+	if err != nil {
+		log.Error("failed to receive iphones", logger.Err(err))
+		return nil, 0, errs.NewAppError(op, err)
+	}
+	log.Info("iphones received", "count", len(iphones), "total", total)
+	return iphones, total, nil
+}
+
+func (is *iPhoneService) History(ctx context.Context, id string, from, to time.Time, bucket string) (*models.PriceHistory, error) {
+	op := place + "History"
+	log := is.Logger.AddOp(op)
+	log.Info("iphone history receiving", "id", id)
+	if _, err := is.IPhoneRepository.Get(ctx, id); err != nil {
+		log.Error("failed to receive iphone", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	records, err := is.PriceHistoryRepository.FetchByRange(ctx, id, from, to)
+	if err != nil {
+		log.Error("failed to receive price records", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	history := &models.PriceHistory{
+		IPhoneId: id,
+		From:     from,
+		To:       to,
+		Bucket:   bucket,
+		Buckets:  aggregatePrices(records, bucket),
+	}
+	extremes, err := is.PriceHistoryRepository.FetchExtremes(ctx, id)
+	if err != nil && !errors.Is(err, errs.ErrNotFoundBase) {
+		log.Error("failed to receive price extremes", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	history.AllTime = extremes
+	log.Info("iphone history received", "id", id, "buckets", len(history.Buckets))
+	return history, nil
+}
+
+func (is *iPhoneService) Subscribe(ctx context.Context, id string, contact models.Contacts) error {
+	op := place + "Subscribe"
+	log := is.Logger.AddOp(op)
+	log.Info("subscribing on iphone stock", "id", id)
+	if _, err := is.IPhoneRepository.Get(ctx, id); err != nil {
+		log.Error("failed to receive iphone", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	if err := is.StockSubscriptionRepository.Create(ctx, id, contact); err != nil {
+		log.Error("failed to create stock subscription", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	log.Info("subscribed on iphone stock", "id", id)
+	return nil
+}
+
+func (is *iPhoneService) Unsubscribe(ctx context.Context, id string, contact models.Contacts) error {
+	op := place + "Unsubscribe"
+	log := is.Logger.AddOp(op)
+	log.Info("unsubscribing from iphone stock", "id", id)
+	if err := is.StockSubscriptionRepository.Delete(ctx, id, contact); err != nil {
+		log.Error("failed to delete stock subscription", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	log.Info("unsubscribed from iphone stock", "id", id)
+	return nil
+}
+
+func bucketStart(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if bucket == models.BucketWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func aggregatePrices(records []models.PriceRecord, bucket string) []models.PriceBucket {
+	buckets := []models.PriceBucket{}
+	for _, r := range records {
+		if r.Price <= 0 {
+			continue
+		}
+		start := bucketStart(r.CreatedAt, bucket)
+		if n := len(buckets); n > 0 && buckets[n-1].Start.Equal(start) {
+			b := &buckets[n-1]
+			b.Min = math.Min(b.Min, r.Price)
+			b.Max = math.Max(b.Max, r.Price)
+			b.Close = r.Price
+			b.Count++
+			continue
+		}
+		buckets = append(buckets, models.PriceBucket{
+			Start: start,
+			Open:  r.Price,
+			Min:   r.Price,
+			Max:   r.Price,
+			Close: r.Price,
+			Count: 1,
+		})
+	}
+	return buckets
+}
+
 func (is *iPhoneService) Update(ctx context.Context, id string) (*models.IPhone, error) {
 	op := place + "update"
 	log := is.Logger.AddOp(op)
 	log.Info("iphone updating", "id", id)
+	iphone, err := is.IPhoneRepository.Get(ctx, id)
+	if err != nil {
+		log.Error("failed to receive iphone", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	return is.update(ctx, iphone)
+}
+
+var errNoOffers = errors.New("iphone has no offers")
+
+func (is *iPhoneService) update(ctx context.Context, iphone *models.IPhone) (*models.IPhone, error) {
+	op := place + "update"
+	log := is.Logger.AddOp(op)
+	offers, err := is.OfferRepository.FetchByIPhone(ctx, iphone.Id)
+	if err != nil {
+		log.Error("failed to receive offers", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+	if len(offers) == 0 {
+		return nil, errs.NewAppError(op, errNoOffers)
+	}
+
+	records := []models.PriceRecord{}
+	checked := []models.Offer{}
+	for i, offer := range offers {
+		var iphoneData *models.IPhone
+		iphoneData, err = is.fetchOffer(offer)
+		if err != nil {
+			log.Error("failed to receive offer data", "url", offer.Url, logger.Err(err))
+			continue
+		}
+		now := time.Now()
+		availability := iphoneData.Availability
+		if availability == "" {
+			availability = models.AvailabilityInStock
+		}
+		if iphoneData.Price > 0 {
+			offers[i].Price = iphoneData.Price
+			offers[i].OldPrice = iphoneData.OldPrice
+			offers[i].Installment = iphoneData.Installment
+		}
+		// a page without a price is still a check, history keeps it as unavailable.
+		records = append(records, models.PriceRecord{
+			IPhoneId:  iphone.Id,
+			Source:    iphoneData.Source,
+			Price:     iphoneData.Price,
+			Available: iphoneData.Price > 0 && availability != models.AvailabilityOutOfStock,
+			CreatedAt: now,
+		})
+		offers[i].Currency = iphoneData.Currency
+		offers[i].Sku = iphoneData.Sku
+		offers[i].Availability = availability
+		offers[i].CheckedAt = &now
+		checked = append(checked, offers[i])
+	}
+	if len(checked) == 0 {
+		return nil, errs.NewAppError(op, err)
+	}
+	best, spread := bestOffer(checked)
+	availability := overallAvailability(checked)
+	update := models.IPhoneUpdate{
+		Price:        iphone.Price,
+		OldPrice:     iphone.OldPrice,
+		Discount:     iphone.Discount,
+		Installment:  iphone.Installment,
+		Availability: availability,
+		Offers:       checked,
+		Records:      records,
+	}
+	if best != nil {
+		update.Repriced = true
+		update.Price = best.Price
+		update.OldPrice = best.OldPrice
+		update.Discount = discount(best.Price, best.OldPrice)
+		update.Installment = best.Installment
+	}
+
+	is.Mutex.Lock()
+	defer is.Mutex.Unlock()
+	updated, err := is.IPhoneRepository.Update(ctx, iphone.Id, update)
+	if err != nil {
+		log.Error("failed to update iphone", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
+	}
+
+	updated.Offers = offers
+	updated.BestOffer = best
+	updated.Spread = spread
+	updated.BackInStock = iphone.Availability != "" && iphone.Availability != models.AvailabilityInStock && availability == models.AvailabilityInStock
+	log.Info("iphone updated", "id", iphone.Id, "availability", availability, "back_in_stock", updated.BackInStock)
+	return updated, nil
+}
+
+func discount(price, oldPrice float64) float64 {
+	if oldPrice <= price || oldPrice <= 0 {
+		return 0
+	}
+	return math.Round((oldPrice-price)/oldPrice*1000) / 10
+}
+
+func overallAvailability(offers []models.Offer) string {
+	availability := models.AvailabilityOutOfStock
+	for _, o := range offers {
+		switch o.Availability {
+		case models.AvailabilityInStock, "":
+			return models.AvailabilityInStock
+		case models.AvailabilityPreorder:
+			availability = models.AvailabilityPreorder
+		}
+	}
+	return availability
+}
+
+func bestOffer(offers []models.Offer) (*models.Offer, float64) {
+	var best, worst *models.Offer
+	for i := range offers {
+		o := &offers[i]
+		if o.CheckedAt == nil || o.Price <= 0 || o.Availability == models.AvailabilityOutOfStock {
+			continue
+		}
+		if best == nil || o.Price < best.Price {
+			best = o
+		}
+		if worst == nil || o.Price > worst.Price {
+			worst = o
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	b := *best
+	return &b, worst.Price - best.Price
+}
+
+func (is *iPhoneService) fetchOffer(offer models.Offer) (*models.IPhone, error) {
+	op := place + "fetchOffer"
+	log := is.Logger.AddOp(op)
 	const (
 		maxRetries = 5
 		baseDelay  = time.Second
@@ -67,43 +330,27 @@ func (is *iPhoneService) Update(ctx context.Context, id string) (*models.IPhone,
 	var err error
 	iphoneData := &models.IPhone{}
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		iphoneData, err = is.ApiClient.GetIPhoneData(id)
+		iphoneData, err = is.ApiClient.GetIPhoneData(offer.Url)
 		if err == nil {
-			break
+			return iphoneData, nil
 		}
 		log.Error("failed to receive iphone data, retrying", logger.Err(err))
 		delay := time.Duration(math.Min(float64(baseDelay)*math.Pow(2, float64(attempt)), float64(maxDelay)))
 		time.Sleep(delay)
 	}
-	if err != nil {
-		return nil, errs.NewAppError(op, err)
-	}
-
-	is.Mutex.Lock()
-	defer is.Mutex.Unlock()
-	iphone, err := is.IPhoneRepository.Update(ctx, id, iphoneData.Price)
-	if err != nil {
-		log.Error("failed to update iphone", logger.Err(err))
-		return nil, errs.NewAppError(op, err)
-	}
-
-	log.Info("iphone updated", "id", id)
-	return iphone, nil
+	return nil, errs.NewAppError(op, err)
 }
 
 func (is *iPhoneService) UpdateAll() ([]models.IPhone, error) {
 	op := place + "updateAll"
 	log := is.Logger.AddOp(op)
 	log.Info("updating all iphones")
-	iphones := []string{
-		is.IPhonesConfig.Black,
-		is.IPhonesConfig.Green,
-		is.IPhonesConfig.White,
-		is.IPhonesConfig.Blue,
-		is.IPhonesConfig.Pink,
-		is.IPhonesConfig.BlackEsim,
-		is.IPhonesConfig.BlueEsim,
-		is.IPhonesConfig.PinkEsim,
+	fetchCtx, cancel := context.WithTimeout(context.Background(), is.IPhonesConfig.Timeout)
+	defer cancel()
+	iphones, err := is.IPhoneRepository.FetchActive(fetchCtx)
+	if err != nil {
+		log.Error("failed to fetch active iphones", logger.Err(err))
+		return nil, errs.NewAppError(op, err)
 	}
 
 	type errStruct struct {
@@ -116,19 +363,16 @@ func (is *iPhoneService) UpdateAll() ([]models.IPhone, error) {
 	var wg sync.WaitGroup
 
 	for _, v := range iphones {
-		if len(v) == 0 {
-			continue
-		}
 		wg.Add(1)
-		go func(id string) {
+		go func(iphone models.IPhone) {
 			defer wg.Done()
 			ctx, cancel := context.WithTimeout(context.Background(), is.IPhonesConfig.Timeout)
 			defer cancel()
-			iphone, err := is.Update(ctx, id)
+			updated, err := is.update(ctx, &iphone)
 			if err != nil {
-				errChan <- errStruct{err: err, id: id}
+				errChan <- errStruct{err: err, id: iphone.Id}
 			} else {
-				iphoneChan <- *iphone
+				iphoneChan <- *updated
 			}
 		}(v)
 	}
@@ -137,14 +381,21 @@ func (is *iPhoneService) UpdateAll() ([]models.IPhone, error) {
 	close(errChan)
 	close(iphoneChan)
 
-	for err := range errChan {
-		return nil, errs.NewAppError(op, err.err)
+	failed := 0
+	var lastErr error
+	for e := range errChan {
+		failed++
+		lastErr = e.err
+		log.Error("failed to update iphone", "id", e.id, logger.Err(e.err))
+	}
+	if failed > 0 && failed == len(iphones) {
+		return nil, errs.NewAppError(op, lastErr)
 	}
 
 	iphonesData := []models.IPhone{}
 	for i := range iphoneChan {
 		iphonesData = append(iphonesData, i)
 	}
-	log.Info("all iphones updated")
+	log.Info("all iphones updated", "updated", len(iphonesData), "failed", failed)
 	return iphonesData, nil
 }
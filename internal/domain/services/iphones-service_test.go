@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	mock_client "iFall/internal/client/mocks"
 	"iFall/internal/config"
 	"iFall/internal/domain/models"
@@ -9,7 +10,9 @@ import (
 	mock_email "iFall/internal/email/mocks"
 	"iFall/pkg/errs"
 	"iFall/pkg/logger"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +20,8 @@ import (
 
 func TestIPhoneService_Get(t *testing.T) {
 
-	type mockBehavior = func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, id string)
+	type mockBehavior = func(m *mock_repositories.MockIPhoneRepository, mo *mock_repositories.MockOfferRepository, ctx context.Context, id string)
+	checkedAt := time.Date(2025, 11, 19, 15, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		testName       string
@@ -36,8 +40,15 @@ func TestIPhoneService_Get(t *testing.T) {
 				Price:  1000,
 				Change: 0,
 				Color:  "000000",
+				Offers: []models.Offer{
+					{Id: 1, Source: "newton.by", Url: "https://newton.by/iphone1", Price: 1000, CheckedAt: &checkedAt},
+					{Id: 2, Source: "other.by", Url: "https://other.by/iphone1", Price: 1150, CheckedAt: &checkedAt},
+					{Id: 3, Source: "new.by", Url: "https://new.by/iphone1"},
+				},
+				BestOffer: &models.Offer{Id: 1, Source: "newton.by", Url: "https://newton.by/iphone1", Price: 1000, CheckedAt: &checkedAt},
+				Spread:    150,
 			},
-			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, id string) {
+			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, mo *mock_repositories.MockOfferRepository, ctx context.Context, id string) {
 				m.EXPECT().Get(ctx, id).Return(&models.IPhone{
 					Id:     "00000000-0000-0000-0000-000000000000",
 					Name:   "iphone1",
@@ -45,6 +56,11 @@ func TestIPhoneService_Get(t *testing.T) {
 					Change: 0,
 					Color:  "000000",
 				}, nil)
+				mo.EXPECT().FetchByIPhone(ctx, id).Return([]models.Offer{
+					{Id: 1, Source: "newton.by", Url: "https://newton.by/iphone1", Price: 1000, CheckedAt: &checkedAt},
+					{Id: 2, Source: "other.by", Url: "https://other.by/iphone1", Price: 1150, CheckedAt: &checkedAt},
+					{Id: 3, Source: "new.by", Url: "https://new.by/iphone1"},
+				}, nil)
 			},
 		},
 		{
@@ -52,7 +68,7 @@ func TestIPhoneService_Get(t *testing.T) {
 			id:             "00000000-0000-0000-0000-000000000000",
 			expectedError:  errs.ErrNotFoundBase,
 			expectedResult: nil,
-			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, id string) {
+			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, mo *mock_repositories.MockOfferRepository, ctx context.Context, id string) {
 				m.EXPECT().Get(ctx, id).Return(nil, errs.ErrNotFoundBase)
 			},
 		},
@@ -66,8 +82,10 @@ func TestIPhoneService_Get(t *testing.T) {
 			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
 			emailSender := mock_email.NewMockEmailSender(c)
 			ctx := context.Background()
-			tt.mockBehavior(iphoneRepo, ctx, tt.id)
-			iphoneService := NewIPhoneService(iphoneRepo, client, logger, emailSender, config.IPhonesConfig{})
+			historyRepo := mock_repositories.NewMockPriceHistoryRepository(c)
+			offerRepo := mock_repositories.NewMockOfferRepository(c)
+			tt.mockBehavior(iphoneRepo, offerRepo, ctx, tt.id)
+			iphoneService := NewIPhoneService(iphoneRepo, historyRepo, offerRepo, mock_repositories.NewMockStockSubscriptionRepository(c), client, logger, emailSender, config.IPhonesConfig{})
 			iphone, err := iphoneService.Get(ctx, tt.id)
 			assert.Equal(t, tt.expectedResult, iphone)
 			if tt.expectedError == nil {
@@ -79,13 +97,190 @@ func TestIPhoneService_Get(t *testing.T) {
 	}
 }
 
+func TestIPhoneService_Fetch(t *testing.T) {
+	type mockBehavior = func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, filter models.IPhoneFilter)
+
+	tests := []struct {
+		testName       string
+		filter         models.IPhoneFilter
+		mockBehavior   mockBehavior
+		expectedTotal  int
+		expectedError  error
+		expectedResult []models.IPhone
+	}{
+		{
+			testName:      "success receiving",
+			filter:        models.IPhoneFilter{ColorName: "green", Limit: 20},
+			expectedTotal: 1,
+			expectedError: nil,
+			expectedResult: []models.IPhone{
+				{Id: "iphone-green-id", Name: "iphone-green", Price: 950, ColorName: "green", Active: true},
+			},
+			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, filter models.IPhoneFilter) {
+				m.EXPECT().Fetch(ctx, filter).Return([]models.IPhone{
+					{Id: "iphone-green-id", Name: "iphone-green", Price: 950, ColorName: "green", Active: true},
+				}, 1, nil)
+			},
+		},
+		{
+			testName:       "failed receiving",
+			filter:         models.IPhoneFilter{Limit: 20},
+			expectedTotal:  0,
+			expectedError:  errs.ErrNotFoundBase,
+			expectedResult: nil,
+			mockBehavior: func(m *mock_repositories.MockIPhoneRepository, ctx context.Context, filter models.IPhoneFilter) {
+				m.EXPECT().Fetch(ctx, filter).Return(nil, 0, errs.ErrNotFoundBase)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			iphoneRepo := mock_repositories.NewMockIPhoneRepository(c)
+			historyRepo := mock_repositories.NewMockPriceHistoryRepository(c)
+			client := mock_client.NewMockApiClient(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			emailSender := mock_email.NewMockEmailSender(c)
+			ctx := context.Background()
+			tt.mockBehavior(iphoneRepo, ctx, tt.filter)
+			offerRepo := mock_repositories.NewMockOfferRepository(c)
+			iphoneService := NewIPhoneService(iphoneRepo, historyRepo, offerRepo, mock_repositories.NewMockStockSubscriptionRepository(c), client, logger, emailSender, config.IPhonesConfig{})
+			iphones, total, err := iphoneService.Fetch(ctx, tt.filter)
+			assert.Equal(t, tt.expectedResult, iphones)
+			assert.Equal(t, tt.expectedTotal, total)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestIPhoneService_History(t *testing.T) {
+	type mockBehavior = func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, ctx context.Context)
+	monday := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	from := monday
+	to := monday.AddDate(0, 0, 14)
+	records := []models.PriceRecord{
+		{IPhoneId: "iphone-black-id", Price: 1000, CreatedAt: monday.Add(9 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Price: 0, CreatedAt: monday.Add(12 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Price: 950, CreatedAt: monday.Add(15 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Price: 980, CreatedAt: monday.Add(21 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Price: 900, CreatedAt: monday.AddDate(0, 0, 3).Add(15 * time.Hour)},
+		{IPhoneId: "iphone-black-id", Price: 920, CreatedAt: monday.AddDate(0, 0, 8).Add(15 * time.Hour)},
+	}
+
+	tests := []struct {
+		testName       string
+		bucket         string
+		mockBehavior   mockBehavior
+		expectedError  error
+		expectedResult *models.PriceHistory
+	}{
+		{
+			testName: "success daily buckets",
+			bucket:   models.BucketDay,
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, ctx context.Context) {
+				mr.EXPECT().Get(ctx, "iphone-black-id").Return(&models.IPhone{Id: "iphone-black-id"}, nil)
+				mh.EXPECT().FetchByRange(ctx, "iphone-black-id", from, to).Return(records, nil)
+				mh.EXPECT().FetchExtremes(ctx, "iphone-black-id").Return(&models.PriceExtremes{Min: 850, Max: 1100}, nil)
+			},
+			expectedError: nil,
+			expectedResult: &models.PriceHistory{
+				IPhoneId: "iphone-black-id",
+				From:     from,
+				To:       to,
+				Bucket:   models.BucketDay,
+				Buckets: []models.PriceBucket{
+					{Start: monday, Open: 1000, Min: 950, Max: 1000, Close: 980, Count: 3},
+					{Start: monday.AddDate(0, 0, 3), Open: 900, Min: 900, Max: 900, Close: 900, Count: 1},
+					{Start: monday.AddDate(0, 0, 8), Open: 920, Min: 920, Max: 920, Close: 920, Count: 1},
+				},
+				AllTime: &models.PriceExtremes{Min: 850, Max: 1100},
+			},
+		},
+		{
+			testName: "success weekly buckets",
+			bucket:   models.BucketWeek,
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, ctx context.Context) {
+				mr.EXPECT().Get(ctx, "iphone-black-id").Return(&models.IPhone{Id: "iphone-black-id"}, nil)
+				mh.EXPECT().FetchByRange(ctx, "iphone-black-id", from, to).Return(records, nil)
+				mh.EXPECT().FetchExtremes(ctx, "iphone-black-id").Return(&models.PriceExtremes{Min: 850, Max: 1100}, nil)
+			},
+			expectedError: nil,
+			expectedResult: &models.PriceHistory{
+				IPhoneId: "iphone-black-id",
+				From:     from,
+				To:       to,
+				Bucket:   models.BucketWeek,
+				Buckets: []models.PriceBucket{
+					{Start: monday, Open: 1000, Min: 900, Max: 1000, Close: 900, Count: 4},
+					{Start: monday.AddDate(0, 0, 7), Open: 920, Min: 920, Max: 920, Close: 920, Count: 1},
+				},
+				AllTime: &models.PriceExtremes{Min: 850, Max: 1100},
+			},
+		},
+		{
+			testName: "success without records",
+			bucket:   models.BucketDay,
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, ctx context.Context) {
+				mr.EXPECT().Get(ctx, "iphone-black-id").Return(&models.IPhone{Id: "iphone-black-id"}, nil)
+				mh.EXPECT().FetchByRange(ctx, "iphone-black-id", from, to).Return([]models.PriceRecord{}, nil)
+				mh.EXPECT().FetchExtremes(ctx, "iphone-black-id").Return(nil, errs.ErrNotFound("test-op"))
+			},
+			expectedError: nil,
+			expectedResult: &models.PriceHistory{
+				IPhoneId: "iphone-black-id",
+				From:     from,
+				To:       to,
+				Bucket:   models.BucketDay,
+				Buckets:  []models.PriceBucket{},
+				AllTime:  nil,
+			},
+		},
+		{
+			testName: "iphone not found",
+			bucket:   models.BucketDay,
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, ctx context.Context) {
+				mr.EXPECT().Get(ctx, "iphone-black-id").Return(nil, errs.ErrNotFoundBase)
+			},
+			expectedError:  errs.ErrNotFoundBase,
+			expectedResult: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			iphoneRepo := mock_repositories.NewMockIPhoneRepository(c)
+			historyRepo := mock_repositories.NewMockPriceHistoryRepository(c)
+			client := mock_client.NewMockApiClient(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			emailSender := mock_email.NewMockEmailSender(c)
+			ctx := context.Background()
+			tt.mockBehavior(iphoneRepo, historyRepo, ctx)
+			offerRepo := mock_repositories.NewMockOfferRepository(c)
+			iphoneService := NewIPhoneService(iphoneRepo, historyRepo, offerRepo, mock_repositories.NewMockStockSubscriptionRepository(c), client, logger, emailSender, config.IPhonesConfig{})
+			history, err := iphoneService.History(ctx, "iphone-black-id", from, to, tt.bucket)
+			assert.Equal(t, tt.expectedResult, history)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
 func TestIphoneService_Update(t *testing.T) {
 	type ttData struct {
 		id             string
 		expectedResult *models.IPhone
 		expectedError  error
 	}
-	type mockBehavior = func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData)
+	type mockBehavior = func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData)
 	tests := []struct {
 		testName     string
 		ttData       ttData
@@ -105,21 +300,36 @@ func TestIphoneService_Update(t *testing.T) {
 				expectedError: nil,
 			},
 
-			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
 				gomock.InOrder(
-					mc.EXPECT().GetIPhoneData(ttData.id).Return(&models.IPhone{
+					mr.EXPECT().Get(ctx, ttData.id).Return(&models.IPhone{
+						Id: "iphone1-id",
+					}, nil),
+					mo.EXPECT().FetchByIPhone(ctx, ttData.id).Return([]models.Offer{
+						{Id: 1, IPhoneId: "iphone1-id", Source: "newton.by", Url: "https://newton.by/iphone1-id"},
+					}, nil),
+					mc.EXPECT().GetIPhoneData("https://newton.by/iphone1-id").Return(&models.IPhone{
 						Id:    "iphone1-id",
 						Name:  "iphone1",
 						Price: 900.0,
 						Color: "ffffff",
 					}, nil),
-					mr.EXPECT().Update(ctx, ttData.id, 900.0).Return(&models.IPhone{
-						Id:     "iphone1-id",
-						Name:   "iphone1",
-						Price:  900.0,
-						Change: 100.0,
-						Color:  "ffffff",
-					}, ttData.expectedError),
+					mr.EXPECT().Update(ctx, ttData.id, iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).DoAndReturn(func(_ context.Context, _ string, update models.IPhoneUpdate) (*models.IPhone, error) {
+						assert.Len(t, update.Offers, 1)
+						assert.Equal(t, int64(1), update.Offers[0].Id)
+						assert.Equal(t, 900.0, update.Offers[0].Price)
+						assert.Len(t, update.Records, 1)
+						assert.Equal(t, "iphone1-id", update.Records[0].IPhoneId)
+						assert.Equal(t, 900.0, update.Records[0].Price)
+						assert.True(t, update.Records[0].Available)
+						return &models.IPhone{
+							Id:     "iphone1-id",
+							Name:   "iphone1",
+							Price:  900.0,
+							Change: 100.0,
+							Color:  "ffffff",
+						}, ttData.expectedError
+					}),
 				)
 			},
 		},
@@ -131,18 +341,144 @@ func TestIphoneService_Update(t *testing.T) {
 				expectedError:  errs.ErrNotFoundBase,
 			},
 
-			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
 				gomock.InOrder(
-					mc.EXPECT().GetIPhoneData(ttData.id).Return(&models.IPhone{
+					mr.EXPECT().Get(ctx, ttData.id).Return(&models.IPhone{
+						Id: "iphone1-id",
+					}, nil),
+					mo.EXPECT().FetchByIPhone(ctx, ttData.id).Return([]models.Offer{
+						{Id: 1, IPhoneId: "iphone1-id", Source: "newton.by", Url: "https://newton.by/iphone1-id"},
+					}, nil),
+					mc.EXPECT().GetIPhoneData("https://newton.by/iphone1-id").Return(&models.IPhone{
 						Id:    "iphone1-id",
 						Name:  "iphone1",
 						Price: 900.0,
 						Color: "ffffff",
 					}, nil),
-					mr.EXPECT().Update(ctx, ttData.id, 900.0).Return(nil, errs.ErrNotFoundBase),
+					mr.EXPECT().Update(ctx, ttData.id, iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(nil, errs.ErrNotFoundBase),
 				)
 			},
 		},
+		{
+			testName: "cheapest source wins",
+			ttData: ttData{
+				id: "iphone1-id",
+				expectedResult: &models.IPhone{
+					Id:     "iphone1-id",
+					Name:   "iphone1",
+					Price:  880.0,
+					Change: -20.0,
+					Color:  "ffffff",
+					Spread: 20.0,
+				},
+				expectedError: nil,
+			},
+
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().Get(ctx, ttData.id).Return(&models.IPhone{Id: "iphone1-id"}, nil)
+				mo.EXPECT().FetchByIPhone(ctx, ttData.id).Return([]models.Offer{
+					{Id: 1, IPhoneId: "iphone1-id", Source: "newton.by", Url: "https://newton.by/iphone1-id"},
+					{Id: 2, IPhoneId: "iphone1-id", Source: "other.by", Url: "https://other.by/iphone1-id"},
+				}, nil)
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone1-id").Return(&models.IPhone{Price: 900.0, Source: "newton.by"}, nil)
+				mc.EXPECT().GetIPhoneData("https://other.by/iphone1-id").Return(&models.IPhone{Price: 880.0, Source: "other.by"}, nil)
+				mr.EXPECT().Update(ctx, ttData.id, iphoneUpdate(models.IPhoneUpdate{Price: 880.0, Availability: models.AvailabilityInStock, Repriced: true})).DoAndReturn(func(_ context.Context, _ string, update models.IPhoneUpdate) (*models.IPhone, error) {
+					assert.Len(t, update.Offers, 2)
+					assert.Equal(t, int64(1), update.Offers[0].Id)
+					assert.Equal(t, 900.0, update.Offers[0].Price)
+					assert.Equal(t, int64(2), update.Offers[1].Id)
+					assert.Equal(t, 880.0, update.Offers[1].Price)
+					assert.Len(t, update.Records, 2)
+					assert.Equal(t, "newton.by", update.Records[0].Source)
+					assert.Equal(t, 900.0, update.Records[0].Price)
+					assert.Equal(t, "other.by", update.Records[1].Source)
+					assert.Equal(t, 880.0, update.Records[1].Price)
+					return &models.IPhone{
+						Id:     "iphone1-id",
+						Name:   "iphone1",
+						Price:  880.0,
+						Change: -20.0,
+						Color:  "ffffff",
+					}, nil
+				})
+			},
+		},
+		{
+			testName: "back in stock",
+			ttData: ttData{
+				id: "iphone1-id",
+				expectedResult: &models.IPhone{
+					Id:           "iphone1-id",
+					Name:         "iphone1",
+					Price:        900.0,
+					Availability: models.AvailabilityInStock,
+					BackInStock:  true,
+				},
+				expectedError: nil,
+			},
+
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().Get(ctx, ttData.id).Return(&models.IPhone{Id: "iphone1-id", Price: 900.0, Availability: models.AvailabilityOutOfStock}, nil)
+				mo.EXPECT().FetchByIPhone(ctx, ttData.id).Return([]models.Offer{
+					{Id: 1, IPhoneId: "iphone1-id", Source: "newton.by", Url: "https://newton.by/iphone1-id"},
+				}, nil)
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone1-id").Return(&models.IPhone{Price: 900.0, Source: "newton.by", Availability: models.AvailabilityInStock}, nil)
+				mr.EXPECT().Update(ctx, ttData.id, iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
+					Id:           "iphone1-id",
+					Name:         "iphone1",
+					Price:        900.0,
+					Availability: models.AvailabilityInStock,
+				}, nil)
+			},
+		},
+		{
+			testName: "out of stock keeps price",
+			ttData: ttData{
+				id: "iphone1-id",
+				expectedResult: &models.IPhone{
+					Id:           "iphone1-id",
+					Name:         "iphone1",
+					Price:        950.0,
+					Availability: models.AvailabilityOutOfStock,
+				},
+				expectedError: nil,
+			},
+
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().Get(ctx, ttData.id).Return(&models.IPhone{Id: "iphone1-id", Price: 950.0, Availability: models.AvailabilityInStock}, nil)
+				mo.EXPECT().FetchByIPhone(ctx, ttData.id).Return([]models.Offer{
+					{Id: 1, IPhoneId: "iphone1-id", Source: "newton.by", Url: "https://newton.by/iphone1-id", Price: 950.0},
+				}, nil)
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone1-id").Return(&models.IPhone{Source: "newton.by", Availability: models.AvailabilityOutOfStock}, nil)
+				mr.EXPECT().Update(ctx, ttData.id, iphoneUpdate(models.IPhoneUpdate{Price: 950.0, Availability: models.AvailabilityOutOfStock})).DoAndReturn(func(_ context.Context, _ string, update models.IPhoneUpdate) (*models.IPhone, error) {
+					assert.Len(t, update.Offers, 1)
+					assert.Equal(t, models.AvailabilityOutOfStock, update.Offers[0].Availability)
+					assert.Equal(t, 950.0, update.Offers[0].Price)
+					assert.Len(t, update.Records, 1)
+					assert.Equal(t, "iphone1-id", update.Records[0].IPhoneId)
+					assert.Equal(t, 0.0, update.Records[0].Price)
+					assert.False(t, update.Records[0].Available)
+					return &models.IPhone{
+						Id:           "iphone1-id",
+						Name:         "iphone1",
+						Price:        950.0,
+						Availability: models.AvailabilityOutOfStock,
+					}, nil
+				})
+			},
+		},
+		{
+			testName: "not in catalog",
+			ttData: ttData{
+				id:             "iphone2-id",
+				expectedResult: nil,
+				expectedError:  errs.ErrNotFoundBase,
+			},
+
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().Get(ctx, ttData.id).Return(nil, errs.ErrNotFoundBase)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -151,16 +487,20 @@ func TestIphoneService_Update(t *testing.T) {
 			defer c.Finish()
 			mockClient := mock_client.NewMockApiClient(c)
 			mockRepository := mock_repositories.NewMockIPhoneRepository(c)
+			mockHistory := mock_repositories.NewMockPriceHistoryRepository(c)
+			mockOffers := mock_repositories.NewMockOfferRepository(c)
 			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
 			emailSender := mock_email.NewMockEmailSender(c)
 			ctx := context.Background()
-			tt.mockBehavior(mockRepository, mockClient, ctx, tt.ttData)
-			service := NewIPhoneService(mockRepository, mockClient, logger, emailSender, config.IPhonesConfig{})
+			tt.mockBehavior(mockRepository, mockHistory, mockOffers, mockClient, ctx, tt.ttData)
+			service := NewIPhoneService(mockRepository, mockHistory, mockOffers, mock_repositories.NewMockStockSubscriptionRepository(c), mockClient, logger, emailSender, config.IPhonesConfig{})
 			iphone, err := service.Update(ctx, tt.ttData.id)
 			if tt.ttData.expectedError != nil {
 				assert.ErrorIs(t, err, tt.ttData.expectedError)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.ttData.expectedResult.Availability != models.AvailabilityOutOfStock, iphone.BestOffer != nil)
+				iphone.Offers, iphone.BestOffer = nil, nil
 				assert.Equal(t, tt.ttData.expectedResult, iphone)
 			}
 
@@ -173,7 +513,7 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 		expectedResult []models.IPhone
 		expectedError  error
 	}
-	type mockBehavior = func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData)
+	type mockBehavior = func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData)
 	tests := []struct {
 		testName     string
 		ttData       ttData
@@ -207,15 +547,25 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 				},
 				expectedError: nil,
 			},
-			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().FetchActive(gomock.Any()).Return([]models.IPhone{
+					{Id: "iphone-black-id", Active: true},
+					{Id: "iphone-white-id", Active: true},
+					{Id: "iphone-blue-id", Active: true},
+				}, nil)
+				for _, id := range []string{"iphone-black-id", "iphone-white-id", "iphone-blue-id"} {
+					mo.EXPECT().FetchByIPhone(gomock.Any(), id).Return([]models.Offer{
+						{IPhoneId: id, Source: "newton.by", Url: "https://newton.by/" + id},
+					}, nil)
+				}
 
-				mc.EXPECT().GetIPhoneData("iphone-black-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-black-id").Return(&models.IPhone{
 					Id:    "iphone-black-id",
 					Name:  "iphone-black-name",
 					Price: 900.0,
 					Color: "black",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", 900.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-black-id",
 					Name:   "iphone-black-name",
 					Price:  900.0,
@@ -223,13 +573,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "black",
 				}, nil)
 
-				mc.EXPECT().GetIPhoneData("iphone-white-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-white-id").Return(&models.IPhone{
 					Id:    "iphone-white-id",
 					Name:  "iphone-white-name",
 					Price: 920.0,
 					Color: "white",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", 920.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", iphoneUpdate(models.IPhoneUpdate{Price: 920.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-white-id",
 					Name:   "iphone-white-name",
 					Price:  920.0,
@@ -237,13 +587,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "white",
 				}, nil)
 
-				mc.EXPECT().GetIPhoneData("iphone-blue-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-blue-id").Return(&models.IPhone{
 					Id:    "iphone-blue-id",
 					Name:  "iphone-blue-name",
 					Price: 1000.0,
 					Color: "blue",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", 1000.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", iphoneUpdate(models.IPhoneUpdate{Price: 1000.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-blue-id",
 					Name:   "iphone-blue-name",
 					Price:  1000.0,
@@ -259,15 +609,25 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 				expectedResult: nil,
 				expectedError:  errs.ErrNotFoundBase,
 			},
-			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().FetchActive(gomock.Any()).Return([]models.IPhone{
+					{Id: "iphone-black-id", Active: true},
+					{Id: "iphone-white-id", Active: true},
+					{Id: "iphone-blue-id", Active: true},
+				}, nil)
+				for _, id := range []string{"iphone-black-id", "iphone-white-id", "iphone-blue-id"} {
+					mo.EXPECT().FetchByIPhone(gomock.Any(), id).Return([]models.Offer{
+						{IPhoneId: id, Source: "newton.by", Url: "https://newton.by/" + id},
+					}, nil)
+				}
 
-				mc.EXPECT().GetIPhoneData("iphone-black-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-black-id").Return(&models.IPhone{
 					Id:    "iphone-black-id",
 					Name:  "iphone-black-name",
 					Price: 900.0,
 					Color: "black",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", 900.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-black-id",
 					Name:   "iphone-black-name",
 					Price:  900.0,
@@ -275,13 +635,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "black",
 				}, errs.ErrNotFoundBase)
 
-				mc.EXPECT().GetIPhoneData("iphone-white-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-white-id").Return(&models.IPhone{
 					Id:    "iphone-white-id",
 					Name:  "iphone-white-name",
 					Price: 920.0,
 					Color: "white",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", 920.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", iphoneUpdate(models.IPhoneUpdate{Price: 920.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-white-id",
 					Name:   "iphone-white-name",
 					Price:  920.0,
@@ -289,13 +649,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "white",
 				}, errs.ErrNotFoundBase)
 
-				mc.EXPECT().GetIPhoneData("iphone-blue-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-blue-id").Return(&models.IPhone{
 					Id:    "iphone-blue-id",
 					Name:  "iphone-blue-name",
 					Price: 1000.0,
 					Color: "blue",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", 1000.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", iphoneUpdate(models.IPhoneUpdate{Price: 1000.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-blue-id",
 					Name:   "iphone-blue-name",
 					Price:  1000.0,
@@ -308,18 +668,43 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 		{
 			testName: "one not found",
 			ttData: ttData{
-				expectedResult: nil,
-				expectedError:  errs.ErrNotFoundBase,
+				expectedResult: []models.IPhone{
+					{
+						Id:     "iphone-white-id",
+						Name:   "iphone-white-name",
+						Price:  920.0,
+						Change: 20.0,
+						Color:  "white",
+					},
+					{
+						Id:     "iphone-blue-id",
+						Name:   "iphone-blue-name",
+						Price:  1000,
+						Change: 100,
+						Color:  "blue",
+					},
+				},
+				expectedError: nil,
 			},
-			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().FetchActive(gomock.Any()).Return([]models.IPhone{
+					{Id: "iphone-black-id", Active: true},
+					{Id: "iphone-white-id", Active: true},
+					{Id: "iphone-blue-id", Active: true},
+				}, nil)
+				for _, id := range []string{"iphone-black-id", "iphone-white-id", "iphone-blue-id"} {
+					mo.EXPECT().FetchByIPhone(gomock.Any(), id).Return([]models.Offer{
+						{IPhoneId: id, Source: "newton.by", Url: "https://newton.by/" + id},
+					}, nil)
+				}
 
-				mc.EXPECT().GetIPhoneData("iphone-black-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-black-id").Return(&models.IPhone{
 					Id:    "iphone-black-id",
 					Name:  "iphone-black-name",
 					Price: 900.0,
 					Color: "black",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", 900.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-black-id", iphoneUpdate(models.IPhoneUpdate{Price: 900.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-black-id",
 					Name:   "iphone-black-name",
 					Price:  900.0,
@@ -327,13 +712,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "black",
 				}, errs.ErrNotFoundBase)
 
-				mc.EXPECT().GetIPhoneData("iphone-white-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-white-id").Return(&models.IPhone{
 					Id:    "iphone-white-id",
 					Name:  "iphone-white-name",
 					Price: 920.0,
 					Color: "white",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", 920.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-white-id", iphoneUpdate(models.IPhoneUpdate{Price: 920.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-white-id",
 					Name:   "iphone-white-name",
 					Price:  920.0,
@@ -341,13 +726,13 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 					Color:  "white",
 				}, nil)
 
-				mc.EXPECT().GetIPhoneData("iphone-blue-id").Return(&models.IPhone{
+				mc.EXPECT().GetIPhoneData("https://newton.by/iphone-blue-id").Return(&models.IPhone{
 					Id:    "iphone-blue-id",
 					Name:  "iphone-blue-name",
 					Price: 1000.0,
 					Color: "blue",
 				}, nil)
-				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", 1000.0).Return(&models.IPhone{
+				mr.EXPECT().Update(gomock.Any(), "iphone-blue-id", iphoneUpdate(models.IPhoneUpdate{Price: 1000.0, Availability: models.AvailabilityInStock, Repriced: true})).Return(&models.IPhone{
 					Id:     "iphone-blue-id",
 					Name:   "iphone-blue-name",
 					Price:  1000.0,
@@ -357,31 +742,65 @@ func TestIphoneService_UpdateAll(t *testing.T) {
 
 			},
 		},
+		{
+			testName: "empty catalog",
+			ttData: ttData{
+				expectedResult: []models.IPhone{},
+				expectedError:  nil,
+			},
+			mockBehavior: func(mr *mock_repositories.MockIPhoneRepository, mh *mock_repositories.MockPriceHistoryRepository, mo *mock_repositories.MockOfferRepository, mc *mock_client.MockApiClient, ctx context.Context, ttData ttData) {
+				mr.EXPECT().FetchActive(gomock.Any()).Return([]models.IPhone{}, nil)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			repoMock := mock_repositories.NewMockIPhoneRepository(c)
+			historyMock := mock_repositories.NewMockPriceHistoryRepository(c)
+			offersMock := mock_repositories.NewMockOfferRepository(c)
 			clientMock := mock_client.NewMockApiClient(c)
 			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
 			emailMock := mock_email.NewMockEmailSender(c)
-			cfg := config.IPhonesConfig{
-				Black: "iphone-black-id",
-				White: "iphone-white-id",
-				Blue:  "iphone-blue-id",
-			}
+			cfg := config.IPhonesConfig{Timeout: time.Second}
 			ctx := context.Background()
-			service := NewIPhoneService(repoMock, clientMock, logger, emailMock, cfg)
-			tt.mockBehavior(repoMock, clientMock, ctx, tt.ttData)
+			service := NewIPhoneService(repoMock, historyMock, offersMock, mock_repositories.NewMockStockSubscriptionRepository(c), clientMock, logger, emailMock, cfg)
+			tt.mockBehavior(repoMock, historyMock, offersMock, clientMock, ctx, tt.ttData)
 			iphones, err := service.UpdateAll()
 			if tt.ttData.expectedError != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.ttData.expectedError)
 			} else {
 				assert.NoError(t, err)
+				for i := range iphones {
+					assert.NotNil(t, iphones[i].BestOffer)
+					iphones[i].Offers, iphones[i].BestOffer = nil, nil
+				}
 				assert.ElementsMatch(t, tt.ttData.expectedResult, iphones)
 			}
 		})
 	}
 }
+
+type iphoneUpdateMatcher struct {
+	expected models.IPhoneUpdate
+}
+
+// iphoneUpdate matches the iphone fields of an update, offers and records are asserted where they matter.
+func iphoneUpdate(expected models.IPhoneUpdate) gomock.Matcher {
+	return iphoneUpdateMatcher{expected: expected}
+}
+
+func (m iphoneUpdateMatcher) Matches(x any) bool {
+	update, ok := x.(models.IPhoneUpdate)
+	if !ok {
+		return false
+	}
+	update.Offers, update.Records = nil, nil
+	return reflect.DeepEqual(m.expected, update)
+}
+
+func (m iphoneUpdateMatcher) String() string {
+	return fmt.Sprintf("is iphone update %+v", m.expected)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: alerts-service.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAlertService is a mock of AlertService interface.
+type MockAlertService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertServiceMockRecorder
+}
+
+// MockAlertServiceMockRecorder is the mock recorder for MockAlertService.
+type MockAlertServiceMockRecorder struct {
+	mock *MockAlertService
+}
+
+// NewMockAlertService creates a new mock instance.
+func NewMockAlertService(ctrl *gomock.Controller) *MockAlertService {
+	mock := &MockAlertService{ctrl: ctrl}
+	mock.recorder = &MockAlertServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertService) EXPECT() *MockAlertServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAlertService) Create(ctx context.Context, contact models.Contacts, alert models.Alert) (*models.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, contact, alert)
+	ret0, _ := ret[0].(*models.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAlertServiceMockRecorder) Create(ctx, contact, alert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAlertService)(nil).Create), ctx, contact, alert)
+}
+
+// Delete mocks base method.
+func (m *MockAlertService) Delete(ctx context.Context, contact models.Contacts, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, contact, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertServiceMockRecorder) Delete(ctx, contact, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertService)(nil).Delete), ctx, contact, id)
+}
+
+// Evaluate mocks base method.
+func (m *MockAlertService) Evaluate(ctx context.Context, iphones []models.IPhone) ([]models.FiredAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", ctx, iphones)
+	ret0, _ := ret[0].([]models.FiredAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockAlertServiceMockRecorder) Evaluate(ctx, iphones interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockAlertService)(nil).Evaluate), ctx, iphones)
+}
+
+// Fetch mocks base method.
+func (m *MockAlertService) Fetch(ctx context.Context, contact models.Contacts) ([]models.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, contact)
+	ret0, _ := ret[0].([]models.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockAlertServiceMockRecorder) Fetch(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockAlertService)(nil).Fetch), ctx, contact)
+}
+
+// MarkFired mocks base method.
+func (m *MockAlertService) MarkFired(ctx context.Context, fired []models.FiredAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFired", ctx, fired)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFired indicates an expected call of MarkFired.
+func (mr *MockAlertServiceMockRecorder) MarkFired(ctx, fired interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFired", reflect.TypeOf((*MockAlertService)(nil).MarkFired), ctx, fired)
+}
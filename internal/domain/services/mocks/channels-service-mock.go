@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: channels-service.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChannelService is a mock of ChannelService interface.
+type MockChannelService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChannelServiceMockRecorder
+}
+
+// MockChannelServiceMockRecorder is the mock recorder for MockChannelService.
+type MockChannelServiceMockRecorder struct {
+	mock *MockChannelService
+}
+
+// NewMockChannelService creates a new mock instance.
+func NewMockChannelService(ctrl *gomock.Controller) *MockChannelService {
+	mock := &MockChannelService{ctrl: ctrl}
+	mock.recorder = &MockChannelServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChannelService) EXPECT() *MockChannelServiceMockRecorder {
+	return m.recorder
+}
+
+// Channels mocks base method.
+func (m *MockChannelService) Channels() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Channels")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Channels indicates an expected call of Channels.
+func (mr *MockChannelServiceMockRecorder) Channels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Channels", reflect.TypeOf((*MockChannelService)(nil).Channels))
+}
+
+// Fetch mocks base method.
+func (m *MockChannelService) Fetch(ctx context.Context, contact models.Contacts) ([]models.UserChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, contact)
+	ret0, _ := ret[0].([]models.UserChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockChannelServiceMockRecorder) Fetch(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockChannelService)(nil).Fetch), ctx, contact)
+}
+
+// Set mocks base method.
+func (m *MockChannelService) Set(ctx context.Context, contact models.Contacts, channel models.UserChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, contact, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockChannelServiceMockRecorder) Set(ctx, contact, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockChannelService)(nil).Set), ctx, contact, channel)
+}
+
+// ValidTarget mocks base method.
+func (m *MockChannelService) ValidTarget(channel, target string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidTarget", channel, target)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ValidTarget indicates an expected call of ValidTarget.
+func (mr *MockChannelServiceMockRecorder) ValidTarget(channel, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidTarget", reflect.TypeOf((*MockChannelService)(nil).ValidTarget), channel, target)
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	models "iFall/internal/domain/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,22 @@ func (m *MockIPhoneService) EXPECT() *MockIPhoneServiceMockRecorder {
 	return m.recorder
 }
 
+// Fetch mocks base method.
+func (m *MockIPhoneService) Fetch(ctx context.Context, filter models.IPhoneFilter) ([]models.IPhone, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, filter)
+	ret0, _ := ret[0].([]models.IPhone)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockIPhoneServiceMockRecorder) Fetch(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockIPhoneService)(nil).Fetch), ctx, filter)
+}
+
 // Get mocks base method.
 func (m *MockIPhoneService) Get(ctx context.Context, id string) (*models.IPhone, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +67,49 @@ func (mr *MockIPhoneServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIPhoneService)(nil).Get), ctx, id)
 }
 
+// History mocks base method.
+func (m *MockIPhoneService) History(ctx context.Context, id string, from, to time.Time, bucket string) (*models.PriceHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id, from, to, bucket)
+	ret0, _ := ret[0].(*models.PriceHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockIPhoneServiceMockRecorder) History(ctx, id, from, to, bucket interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockIPhoneService)(nil).History), ctx, id, from, to, bucket)
+}
+
+// Subscribe mocks base method.
+func (m *MockIPhoneService) Subscribe(ctx context.Context, id string, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, id, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockIPhoneServiceMockRecorder) Subscribe(ctx, id, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockIPhoneService)(nil).Subscribe), ctx, id, contact)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockIPhoneService) Unsubscribe(ctx context.Context, id string, contact models.Contacts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, id, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockIPhoneServiceMockRecorder) Unsubscribe(ctx, id, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockIPhoneService)(nil).Unsubscribe), ctx, id, contact)
+}
+
 // Update mocks base method.
 func (m *MockIPhoneService) Update(ctx context.Context, id string) (*models.IPhone, error) {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@ package mock_services
 
 import (
 	context "context"
+	models "iFall/internal/domain/models"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,12 +35,29 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockUserService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockUserServiceMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserService)(nil).Authenticate), ctx, token)
+}
+
 // Create mocks base method.
-func (m *MockUserService) Create(ctx context.Context, name, email string, telegram *string) error {
+func (m *MockUserService) Create(ctx context.Context, name, email string, telegram *string) (*models.LinkCode, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, name, email, telegram)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*models.LinkCode)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Create indicates an expected call of Create.
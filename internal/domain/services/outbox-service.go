@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"iFall/internal/domain/repositories"
+	"iFall/internal/notifier"
+	"iFall/pkg/errs"
+	"iFall/pkg/logger"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=outbox-service.go -destination=mocks/outbox-service-mock.go
+type OutboxService interface {
+	Enqueue(ctx context.Context, messages []models.OutboxMessage) error
+	Deliver(ctx context.Context) error
+}
+
+type outboxService struct {
+	OutboxRepository repositories.OutboxRepository
+	Notifiers        *notifier.Registry
+	OutboxConfig     config.OutboxConfig
+	Logger           *logger.Logger
+	mu               sync.Mutex
+}
+
+func NewOutboxService(obr repositories.OutboxRepository, nr *notifier.Registry, l *logger.Logger, cfg config.OutboxConfig) OutboxService {
+	return &outboxService{
+		OutboxRepository: obr,
+		Notifiers:        nr,
+		OutboxConfig:     cfg,
+		Logger:           l,
+	}
+}
+
+const outboxPlace = "outboxService."
+
+var errUnknownChannel = errors.New("unknown outbox channel")
+
+func (obs *outboxService) Enqueue(ctx context.Context, messages []models.OutboxMessage) error {
+	op := outboxPlace + "Enqueue"
+	log := obs.Logger.AddOp(op)
+	if len(messages) == 0 {
+		return nil
+	}
+	if err := obs.OutboxRepository.Enqueue(ctx, messages); err != nil {
+		log.Error("failed to enqueue messages", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	log.Info("messages enqueued", "count", len(messages))
+	return nil
+}
+
+func (obs *outboxService) Deliver(ctx context.Context) error {
+	op := outboxPlace + "Deliver"
+	log := obs.Logger.AddOp(op)
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	messages, err := obs.OutboxRepository.FetchDue(ctx, time.Now(), obs.OutboxConfig.BatchSize)
+	if err != nil {
+		log.Error("failed to fetch due messages", logger.Err(err))
+		return errs.NewAppError(op, err)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	var lastErr error
+	sent := 0
+	for _, m := range messages {
+		m.Attempts++
+		now := time.Now()
+		if err := obs.send(ctx, m); err != nil {
+			reason := err.Error()
+			m.LastError = &reason
+			if m.Attempts >= obs.OutboxConfig.MaxAttempts {
+				m.Status = models.OutboxStatusFailed
+				log.Error("giving up on message", "id", m.Id, "channel", m.Channel, "attempts", m.Attempts, logger.Err(err))
+			} else {
+				m.NextRetryAt = now.Add(outboxBackoff(obs.OutboxConfig, m.Attempts))
+				log.Error("failed to deliver message, will retry", "id", m.Id, "channel", m.Channel, "attempts", m.Attempts, "next_retry_at", m.NextRetryAt, logger.Err(err))
+			}
+		} else {
+			m.Status = models.OutboxStatusSent
+			m.LastError = nil
+			m.SentAt = &now
+			sent++
+		}
+		if err := obs.OutboxRepository.Update(ctx, &m); err != nil {
+			log.Error("failed to update message", "id", m.Id, logger.Err(err))
+			lastErr = err
+		}
+	}
+	log.Info("outbox delivered", "due", len(messages), "sent", sent)
+	if lastErr != nil {
+		return errs.NewAppError(op, lastErr)
+	}
+	return nil
+}
+
+func (obs *outboxService) send(ctx context.Context, m models.OutboxMessage) error {
+	n, ok := obs.Notifiers.Get(m.Channel)
+	if !ok {
+		return errUnknownChannel
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, obs.OutboxConfig.SendTimeout)
+	defer cancel()
+	return n.Send(sendCtx, m.Recipient, notifier.Message{Subject: m.Subject, Body: m.Body})
+}
+
+func outboxBackoff(cfg config.OutboxConfig, attempts int) time.Duration {
+	backoff := cfg.BaseBackoff
+	for i := 1; i < attempts && backoff < cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, cfg.MaxBackoff)
+}
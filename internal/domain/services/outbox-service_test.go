@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	mock_bot "iFall/internal/bot/mocks"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	mock_repositories "iFall/internal/domain/repositories/mocks"
+	mock_email "iFall/internal/email/mocks"
+	"iFall/internal/notifier"
+	"iFall/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxService_Deliver(t *testing.T) {
+	type mockBehavior = func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender)
+	sendingError := errors.New("sending error")
+	repoError := errors.New("repo error")
+	cfg := config.OutboxConfig{BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour, SendTimeout: time.Second}
+	telegram := models.OutboxMessage{Id: 1, Channel: models.ChannelTelegram, Recipient: "111", Body: "цены", Status: models.OutboxStatusPending}
+	mail := models.OutboxMessage{Id: 2, Channel: models.ChannelEmail, Recipient: "sanya@gmail.com", Subject: "цены", Body: "<p>цены</p>", Status: models.OutboxStatusPending, Attempts: 2}
+	tests := []struct {
+		testName      string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			testName: "success delivering",
+			mockBehavior: func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender) {
+				or.EXPECT().FetchDue(gomock.Any(), gomock.Any(), 10).Return([]models.OutboxMessage{telegram, mail}, nil)
+				bm.EXPECT().SendMessage(int64(111), "цены").Return(nil)
+				em.EXPECT().SendMessage(gomock.Any(), "цены", []byte("<p>цены</p>"), []string{"sanya@gmail.com"}, nil).Return(nil)
+				or.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *models.OutboxMessage) error {
+					assert.Equal(t, models.OutboxStatusSent, m.Status)
+					assert.NotNil(t, m.SentAt)
+					return nil
+				}).Times(2)
+			},
+		},
+		{
+			testName: "success rescheduling with backoff",
+			mockBehavior: func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender) {
+				or.EXPECT().FetchDue(gomock.Any(), gomock.Any(), 10).Return([]models.OutboxMessage{telegram}, nil)
+				bm.EXPECT().SendMessage(int64(111), "цены").Return(sendingError)
+				or.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *models.OutboxMessage) error {
+					assert.Equal(t, models.OutboxStatusPending, m.Status)
+					assert.Equal(t, 1, m.Attempts)
+					assert.Equal(t, "sending error", *m.LastError)
+					assert.WithinDuration(t, time.Now().Add(time.Minute), m.NextRetryAt, time.Second)
+					return nil
+				})
+			},
+		},
+		{
+			testName: "success giving up after max attempts",
+			mockBehavior: func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender) {
+				or.EXPECT().FetchDue(gomock.Any(), gomock.Any(), 10).Return([]models.OutboxMessage{mail}, nil)
+				em.EXPECT().SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(sendingError)
+				or.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *models.OutboxMessage) error {
+					assert.Equal(t, models.OutboxStatusFailed, m.Status)
+					assert.Equal(t, 3, m.Attempts)
+					return nil
+				})
+			},
+		},
+		{
+			testName: "failed updating message",
+			mockBehavior: func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender) {
+				or.EXPECT().FetchDue(gomock.Any(), gomock.Any(), 10).Return([]models.OutboxMessage{telegram}, nil)
+				bm.EXPECT().SendMessage(int64(111), "цены").Return(nil)
+				or.EXPECT().Update(gomock.Any(), gomock.Any()).Return(repoError)
+			},
+			expectedError: repoError,
+		},
+		{
+			testName: "failed fetching due messages",
+			mockBehavior: func(or *mock_repositories.MockOutboxRepository, bm *mock_bot.MockTelegramBot, em *mock_email.MockEmailSender) {
+				or.EXPECT().FetchDue(gomock.Any(), gomock.Any(), 10).Return(nil, repoError)
+			},
+			expectedError: repoError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			outboxMockRepo := mock_repositories.NewMockOutboxRepository(c)
+			botMock := mock_bot.NewMockTelegramBot(c)
+			emailMock := mock_email.NewMockEmailSender(c)
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			notifiers := notifier.NewRegistry(notifier.NewTelegramNotifier(botMock), notifier.NewEmailNotifier(emailMock))
+			service := NewOutboxService(outboxMockRepo, notifiers, logger, cfg)
+			tt.mockBehavior(outboxMockRepo, botMock, emailMock)
+			err := service.Deliver(context.Background())
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	cfg := config.OutboxConfig{BaseBackoff: 30 * time.Second, MaxBackoff: 10 * time.Minute}
+	assert.Equal(t, 30*time.Second, outboxBackoff(cfg, 1))
+	assert.Equal(t, time.Minute, outboxBackoff(cfg, 2))
+	assert.Equal(t, 4*time.Minute, outboxBackoff(cfg, 4))
+	assert.Equal(t, 10*time.Minute, outboxBackoff(cfg, 12))
+}
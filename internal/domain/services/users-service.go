@@ -2,32 +2,50 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"iFall/internal/config"
 	"iFall/internal/domain/models"
 	"iFall/internal/domain/repositories"
+	"iFall/internal/utils"
 	"iFall/pkg/errs"
 	"iFall/pkg/logger"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=users-service.go -destination=mocks/users-service-mock.go
 type UserService interface {
-	Create(ctx context.Context, name, email string, telegram *string) error
+	Create(ctx context.Context, name, email string, telegram *string) (*models.LinkCode, string, error)
+	Authenticate(ctx context.Context, token string) (*models.User, error)
 }
 
 type userService struct {
 	UserRepository repositories.UserRepository
+	BotConfig      config.TelegramBotConfig
 	Logger         *logger.Logger
 }
 
-func NewUserService(ur repositories.UserRepository, l *logger.Logger) UserService {
+func NewUserService(ur repositories.UserRepository, l *logger.Logger, cfg config.TelegramBotConfig) UserService {
 	return &userService{
 		UserRepository: ur,
+		BotConfig:      cfg,
 		Logger:         l,
 	}
 }
 
-func (us *userService) Create(ctx context.Context, name, email string, telegram *string) error {
+const (
+	linkCodeLength   = 8
+	linkCodeAttempts = 3
+	// linkCodeAlphabet skips look-alike characters so the code can be typed by hand.
+	linkCodeAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	defaultLinkCodeTtl = 15 * time.Minute
+)
+
+// Create returns the api token in plain text, it is shown once and only its hash is stored.
+func (us *userService) Create(ctx context.Context, name, email string, telegram *string) (*models.LinkCode, string, error) {
 	op := "userService.Create"
 	log := us.Logger.AddOp(op)
 	log.Info("creating user")
@@ -41,8 +59,65 @@ func (us *userService) Create(ctx context.Context, name, email string, telegram
 	}
 	if err := us.UserRepository.Create(ctx, user); err != nil {
 		log.Error("failed to create user", logger.Err(err))
-		return errs.NewAppError(op, err)
+		return nil, "", errs.NewAppError(op, err)
+	}
+	token := utils.NewApiToken()
+	if err := us.UserRepository.SetApiToken(ctx, user.Id, utils.HashApiToken(token)); err != nil {
+		log.Error("failed to set api token", logger.Err(err))
+		return nil, "", errs.NewAppError(op, err)
+	}
+	code, err := us.issueLinkCode(ctx, user.Id)
+	if err != nil {
+		log.Error("failed to issue link code", logger.Err(err))
+		return nil, "", errs.NewAppError(op, err)
 	}
 	log.Info("user created successfully")
-	return nil
+	return code, token, nil
+}
+
+func (us *userService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	op := "userService.Authenticate"
+	if token == "" {
+		return nil, errs.ErrNotFound(op)
+	}
+	user, err := us.UserRepository.FetchByApiToken(ctx, utils.HashApiToken(token))
+	if err != nil {
+		return nil, errs.NewAppError(op, err)
+	}
+	return user, nil
+}
+
+// issueLinkCode retries on the rare collision with a code of another user.
+func (us *userService) issueLinkCode(ctx context.Context, userId uuid.UUID) (*models.LinkCode, error) {
+	ttl := us.BotConfig.LinkCodeTtl
+	if ttl <= 0 {
+		ttl = defaultLinkCodeTtl
+	}
+	var err error
+	for range linkCodeAttempts {
+		code := &models.LinkCode{
+			Code:      newLinkCode(),
+			UserId:    userId,
+			ExpiresAt: time.Now().Add(ttl).UTC(),
+		}
+		if us.BotConfig.Username != "" {
+			code.Link = fmt.Sprintf("https://t.me/%s?start=%s", us.BotConfig.Username, code.Code)
+		}
+		if err = us.UserRepository.CreateLinkCode(ctx, *code); err == nil {
+			return code, nil
+		}
+		if !errors.Is(err, errs.ErrAlreadyExistsBase) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+func newLinkCode() string {
+	b := make([]byte, linkCodeLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = linkCodeAlphabet[int(b[i])%len(linkCodeAlphabet)]
+	}
+	return string(b)
 }
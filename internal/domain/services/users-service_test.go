@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"iFall/internal/config"
 	"iFall/internal/domain/models"
 	mock_repositories "iFall/internal/domain/repositories/mocks"
@@ -9,6 +10,7 @@ import (
 	"iFall/pkg/errs"
 	"iFall/pkg/logger"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -48,6 +50,8 @@ func TestUserService_Create(t *testing.T) {
 					assert.NotEqual(t, uuid.Nil, user.Id)
 					return tt.expectedError
 				})
+				s.EXPECT().SetApiToken(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				s.EXPECT().CreateLinkCode(ctx, gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -66,6 +70,8 @@ func TestUserService_Create(t *testing.T) {
 					assert.NotEqual(t, uuid.Nil, user.Id)
 					return tt.expectedError
 				})
+				s.EXPECT().SetApiToken(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				s.EXPECT().CreateLinkCode(ctx, gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -104,6 +110,57 @@ func TestUserService_Create(t *testing.T) {
 				})
 			},
 		},
+		{
+			testName: "success create after link code collision",
+			ttData: ttData{
+				name:          "sanya",
+				email:         "sanyaemail@gmail.com",
+				telegram:      nil,
+				expectedError: nil,
+			},
+			mockBehavior: func(s *mock_repositories.MockUserRepository, ctx context.Context, tt ttData) {
+				var userId uuid.UUID
+				s.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.User) error {
+					userId = user.Id
+					return nil
+				})
+				s.EXPECT().SetApiToken(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				gomock.InOrder(
+					s.EXPECT().CreateLinkCode(ctx, gomock.Any()).Return(errs.ErrAlreadyExists("test", errors.New("collision"))),
+					s.EXPECT().CreateLinkCode(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, code models.LinkCode) error {
+						assert.Equal(t, userId, code.UserId)
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			testName: "failed to create link code",
+			ttData: ttData{
+				name:          "sanya",
+				email:         "sanyaemail@gmail.com",
+				telegram:      nil,
+				expectedError: errs.ErrNotFoundBase,
+			},
+			mockBehavior: func(s *mock_repositories.MockUserRepository, ctx context.Context, tt ttData) {
+				s.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+				s.EXPECT().SetApiToken(ctx, gomock.Any(), gomock.Any()).Return(nil)
+				s.EXPECT().CreateLinkCode(ctx, gomock.Any()).Return(errs.ErrNotFound("test"))
+			},
+		},
+		{
+			testName: "failed to set api token",
+			ttData: ttData{
+				name:          "sanya",
+				email:         "sanyaemail@gmail.com",
+				telegram:      nil,
+				expectedError: errs.ErrNotFoundBase,
+			},
+			mockBehavior: func(s *mock_repositories.MockUserRepository, ctx context.Context, tt ttData) {
+				s.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+				s.EXPECT().SetApiToken(ctx, gomock.Any(), gomock.Any()).Return(errs.ErrNotFound("test"))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -111,13 +168,73 @@ func TestUserService_Create(t *testing.T) {
 			defer c.Finish()
 			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
 			mockUserRepo := mock_repositories.NewMockUserRepository(c)
-			userService := NewUserService(mockUserRepo, logger)
+			userService := NewUserService(mockUserRepo, logger, config.TelegramBotConfig{Username: "ifall_bot", LinkCodeTtl: time.Minute})
 			ctx := context.Background()
 
 			tt.mockBehavior(mockUserRepo, ctx, tt.ttData)
-			err := userService.Create(context.Background(), tt.name, tt.email, tt.telegram)
+			code, token, err := userService.Create(context.Background(), tt.name, tt.email, tt.telegram)
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, token)
+				assert.Regexp(t, "^["+linkCodeAlphabet+"]{8}$", code.Code)
+				assert.Equal(t, "https://t.me/ifall_bot?start="+code.Code, code.Link)
+				assert.WithinDuration(t, time.Now().Add(time.Minute), code.ExpiresAt, time.Second)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestUserService_Authenticate(t *testing.T) {
+	user := &models.User{Id: uuid.New(), Name: "sanya", Contacts: models.Contacts{Email: "sanya@gmail.com"}}
+
+	type mockBehavior = func(s *mock_repositories.MockUserRepository, ctx context.Context, token string)
+
+	tests := []struct {
+		testName      string
+		token         string
+		mockBehavior  mockBehavior
+		expectedUser  *models.User
+		expectedError error
+	}{
+		{
+			testName: "success",
+			token:    "secret",
+			mockBehavior: func(s *mock_repositories.MockUserRepository, ctx context.Context, token string) {
+				s.EXPECT().FetchByApiToken(ctx, utils.HashApiToken(token)).Return(user, nil)
+			},
+			expectedUser: user,
+		},
+		{
+			testName: "unknown token",
+			token:    "secret",
+			mockBehavior: func(s *mock_repositories.MockUserRepository, ctx context.Context, token string) {
+				s.EXPECT().FetchByApiToken(ctx, utils.HashApiToken(token)).Return(nil, errs.ErrNotFound("test"))
+			},
+			expectedError: errs.ErrNotFoundBase,
+		},
+		{
+			testName:      "empty token",
+			token:         "",
+			mockBehavior:  func(s *mock_repositories.MockUserRepository, ctx context.Context, token string) {},
+			expectedError: errs.ErrNotFoundBase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			logger := logger.NewLogger(config.AppConfig{Name: "test", Env: "test", Version: "test", LogPath: ""})
+			mockUserRepo := mock_repositories.NewMockUserRepository(c)
+			userService := NewUserService(mockUserRepo, logger, config.TelegramBotConfig{})
+			ctx := context.Background()
+
+			tt.mockBehavior(mockUserRepo, ctx, tt.token)
+			got, err := userService.Authenticate(ctx, tt.token)
 			if tt.expectedError == nil {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, got)
 			} else {
 				assert.ErrorIs(t, err, tt.expectedError)
 			}
@@ -10,3 +10,41 @@ type UpdateIPhoneRequest struct {
 	Id       string `json:"id" validate:"required,uuid"`
 	NewPrice int    `json:"new_price" validate:"required,min=1"`
 }
+
+type FetchIPhonesRequest struct {
+	Model     string   `query:"model" validate:"omitempty,min=1"`
+	Color     string   `query:"color" validate:"omitempty,min=1"`
+	Esim      *bool    `query:"esim"`
+	Available *bool    `query:"available"`
+	MinPrice  *float64 `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice  *float64 `query:"max_price" validate:"omitempty,gte=0"`
+	Sort      string   `query:"sort" validate:"omitempty,oneof=name price change checked_at"`
+	Order     string   `query:"order" validate:"omitempty,oneof=asc desc"`
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int      `query:"offset" validate:"omitempty,min=0"`
+}
+
+type IPhoneHistoryRequest struct {
+	From   string `query:"from"`
+	To     string `query:"to"`
+	Bucket string `query:"bucket" validate:"omitempty,oneof=day week"`
+}
+
+type CreateAlertRequest struct {
+	IPhoneId    *string `json:"iphone_id" validate:"omitempty,min=1"`
+	Model       string  `json:"model" validate:"omitempty,min=1"`
+	ColorName   string  `json:"color_name" validate:"omitempty,min=1"`
+	Capacity    int     `json:"capacity" validate:"omitempty,min=1"`
+	Kind        string  `json:"kind" validate:"required,oneof=price discount drop low change"`
+	Direction   string  `json:"direction" validate:"omitempty,oneof=below above any"`
+	Threshold   float64 `json:"threshold" validate:"gte=0"`
+	WindowHours int     `json:"window_hours" validate:"omitempty,min=1,max=8760"`
+	Repeat      string  `json:"repeat" validate:"omitempty,oneof=once rearm cooldown"`
+	Cooldown    int     `json:"cooldown_hours" validate:"omitempty,min=1,max=8760"`
+}
+
+type SetChannelRequest struct {
+	Channel string `json:"channel" validate:"required,min=1"`
+	Target  string `json:"target" validate:"omitempty,max=2048"`
+	Enabled *bool  `json:"enabled" validate:"required"`
+}
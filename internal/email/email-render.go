@@ -10,6 +10,12 @@ import (
 //go:embed templates/email.html
 var verifyEmailHTML string
 
+//go:embed templates/back-in-stock.html
+var backInStockHTML string
+
+//go:embed templates/alerts.html
+var alertsHTML string
+
 func BuildEmailLetter(iphones []models.IPhone) (string, error) {
 
 	funcMap := template.FuncMap{
@@ -31,3 +37,27 @@ func BuildEmailLetter(iphones []models.IPhone) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+func BuildBackInStockLetter(iphone models.IPhone) (string, error) {
+	tmpl, err := template.New("back-in-stock").Parse(backInStockHTML)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, iphone); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func BuildAlertsLetter(alerts []models.FiredAlert) (string, error) {
+	tmpl, err := template.New("alerts").Parse(alertsHTML)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, alerts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE iphones ADD COLUMN url TEXT NOT NULL DEFAULT '';
+ALTER TABLE iphones ADD COLUMN model TEXT NOT NULL DEFAULT '';
+ALTER TABLE iphones ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE iphones ADD COLUMN esim BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE iphones ADD COLUMN color_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE iphones ADD COLUMN active BOOLEAN NOT NULL DEFAULT 1;
+
+UPDATE iphones SET
+    url = 'https://newton.by/mobilnye-telefony/' || id,
+    model = 'iPhone 17',
+    capacity = 256,
+    esim = CASE WHEN id LIKE '%dual_esim%' THEN 1 ELSE 0 END,
+    color_name = CASE
+        WHEN id LIKE '%chernyy' THEN 'black'
+        WHEN id LIKE '%belyy' THEN 'white'
+        WHEN id LIKE '%goluboy' THEN 'blue'
+        WHEN id LIKE '%zelenyy' THEN 'green'
+        WHEN id LIKE '%sirenevyy' THEN 'pink'
+        ELSE ''
+    END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE iphones DROP COLUMN active;
+ALTER TABLE iphones DROP COLUMN color_name;
+ALTER TABLE iphones DROP COLUMN esim;
+ALTER TABLE iphones DROP COLUMN capacity;
+ALTER TABLE iphones DROP COLUMN model;
+ALTER TABLE iphones DROP COLUMN url;
+-- +goose StatementEnd
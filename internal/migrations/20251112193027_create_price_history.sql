@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS price_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    iphone_id TEXT NOT NULL REFERENCES iphones(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    price NUMERIC NOT NULL,
+    available BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_iphone_created ON price_history (iphone_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_price_history_iphone_created;
+DROP TABLE IF EXISTS price_history;
+-- +goose StatementEnd
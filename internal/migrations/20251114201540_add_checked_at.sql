@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE iphones
+ADD COLUMN checked_at DATETIME
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE iphones
+DROP COLUMN checked_at
+-- +goose StatementEnd
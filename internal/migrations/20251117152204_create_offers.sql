@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS offers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    iphone_id TEXT NOT NULL REFERENCES iphones(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_offers_iphone ON offers (iphone_id);
+
+INSERT INTO offers (iphone_id, source, url)
+SELECT id, 'newton.by', url FROM iphones WHERE url != '';
+
+ALTER TABLE iphones DROP COLUMN url;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE iphones ADD COLUMN url TEXT NOT NULL DEFAULT '';
+
+UPDATE iphones SET url = COALESCE(
+    (SELECT url FROM offers WHERE offers.iphone_id = iphones.id ORDER BY offers.id LIMIT 1),
+    ''
+);
+
+DROP INDEX IF EXISTS idx_offers_iphone;
+DROP TABLE IF EXISTS offers;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offers ADD COLUMN price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE offers ADD COLUMN checked_at DATETIME;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE offers DROP COLUMN checked_at;
+ALTER TABLE offers DROP COLUMN price;
+-- +goose StatementEnd
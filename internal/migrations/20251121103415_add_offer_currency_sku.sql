@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offers ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE offers ADD COLUMN sku TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE offers DROP COLUMN sku;
+ALTER TABLE offers DROP COLUMN currency;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE iphones ADD COLUMN availability TEXT NOT NULL DEFAULT 'in_stock';
+ALTER TABLE offers ADD COLUMN availability TEXT NOT NULL DEFAULT 'in_stock';
+
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    iphone_id TEXT NOT NULL REFERENCES iphones(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL,
+    UNIQUE (user_id, iphone_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_subscriptions_iphone ON stock_subscriptions (iphone_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_stock_subscriptions_iphone;
+DROP TABLE IF EXISTS stock_subscriptions;
+ALTER TABLE offers DROP COLUMN availability;
+ALTER TABLE iphones DROP COLUMN availability;
+-- +goose StatementEnd
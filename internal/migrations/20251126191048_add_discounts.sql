@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE iphones ADD COLUMN old_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE iphones ADD COLUMN discount NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE iphones ADD COLUMN installment NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE offers ADD COLUMN old_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE offers ADD COLUMN installment NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN desired_discount NUMERIC NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN desired_discount;
+ALTER TABLE offers DROP COLUMN installment;
+ALTER TABLE offers DROP COLUMN old_price;
+ALTER TABLE iphones DROP COLUMN installment;
+ALTER TABLE iphones DROP COLUMN discount;
+ALTER TABLE iphones DROP COLUMN old_price;
+-- +goose StatementEnd
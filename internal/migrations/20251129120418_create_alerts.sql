@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS alerts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    iphone_id TEXT REFERENCES iphones(id) ON DELETE CASCADE,
+    model TEXT NOT NULL DEFAULT '',
+    color_name TEXT NOT NULL DEFAULT '',
+    capacity INTEGER NOT NULL DEFAULT 0,
+    kind TEXT NOT NULL DEFAULT 'price',
+    direction TEXT NOT NULL DEFAULT 'below',
+    threshold NUMERIC NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_user ON alerts (user_id);
+
+INSERT INTO alerts (user_id, kind, direction, threshold, created_at)
+SELECT id, 'price', 'below', desired_price, CURRENT_TIMESTAMP FROM users WHERE desired_price > 0;
+
+INSERT INTO alerts (user_id, kind, direction, threshold, created_at)
+SELECT id, 'discount', 'above', desired_discount, CURRENT_TIMESTAMP FROM users WHERE desired_discount > 0;
+
+ALTER TABLE users DROP COLUMN desired_discount;
+ALTER TABLE users DROP COLUMN desired_price;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN desired_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN desired_discount NUMERIC NOT NULL DEFAULT 0;
+
+UPDATE users SET desired_price = COALESCE((
+    SELECT MAX(threshold) FROM alerts
+    WHERE alerts.user_id = users.id AND kind = 'price' AND direction = 'below' AND iphone_id IS NULL
+        AND model = '' AND color_name = '' AND capacity = 0
+), 0);
+
+UPDATE users SET desired_discount = COALESCE((
+    SELECT MIN(threshold) FROM alerts
+    WHERE alerts.user_id = users.id AND kind = 'discount' AND iphone_id IS NULL
+        AND model = '' AND color_name = '' AND capacity = 0
+), 0);
+
+DROP INDEX IF EXISTS idx_alerts_user;
+DROP TABLE IF EXISTS alerts;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE alerts ADD COLUMN repeat TEXT NOT NULL DEFAULT 'rearm';
+ALTER TABLE alerts ADD COLUMN cooldown_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN fired BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN last_fired_at DATETIME;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE alerts DROP COLUMN last_fired_at;
+ALTER TABLE alerts DROP COLUMN fired;
+ALTER TABLE alerts DROP COLUMN cooldown_hours;
+ALTER TABLE alerts DROP COLUMN repeat;
+-- +goose StatementEnd
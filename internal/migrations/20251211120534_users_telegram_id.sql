@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users_new (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT UNIQUE,
+    telegram TEXT UNIQUE,
+    chat_id INTEGER UNIQUE,
+    telegram_id INTEGER UNIQUE
+);
+INSERT INTO users_new (id, name, email, telegram, chat_id)
+SELECT id, name, NULLIF(email, ''), telegram, chat_id FROM users;
+-- chats were only stored from private chats with the bot, where chat id equals user id
+UPDATE users_new SET telegram_id = chat_id WHERE chat_id IS NOT NULL;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE users_old (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    email TEXT NOT NULL UNIQUE,
+    telegram TEXT UNIQUE,
+    chat_id INTEGER UNIQUE
+);
+-- users registered in the bot keep a placeholder email and repeated names get an id suffix
+INSERT INTO users_old (id, name, email, telegram, chat_id)
+SELECT id,
+    CASE WHEN EXISTS (SELECT 1 FROM users d WHERE d.name = u.name AND d.id < u.id) THEN u.name || ' ' || substr(u.id, 1, 8) ELSE u.name END,
+    COALESCE(email, id || '@telegram.invalid'), telegram, chat_id
+FROM users u;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+-- +goose StatementEnd
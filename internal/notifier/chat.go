@@ -0,0 +1,333 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	reportTitle = "Обновление цен"
+	alertsTitle = "🚨 сработали ваши алерты"
+
+	infoColor  = "#1565c0"
+	alertColor = "#c62828"
+	stockColor = "#2e7d32"
+
+	slackMaxBlocks    = 50
+	discordMaxFields  = 25
+	discordMaxEmbeds  = 10
+	discordFieldName  = 256
+	discordFieldValue = 1024
+)
+
+// chatNotifier posts to incoming webhooks of team chats, each chat only differs in payload format.
+type chatNotifier struct {
+	Client  *http.Client
+	Urls    []string
+	Timeout time.Duration
+	channel string
+	payload func(n Notification) any
+}
+
+func NewSlackNotifier(c *http.Client, cfg config.ChatConfig) Notifier {
+	return &chatNotifier{Client: c, Urls: cfg.SlackUrls, Timeout: cfg.Timeout, channel: models.ChannelSlack, payload: slackPayload}
+}
+
+func NewDiscordNotifier(c *http.Client, cfg config.ChatConfig) Notifier {
+	return &chatNotifier{Client: c, Urls: cfg.DiscordUrls, Timeout: cfg.Timeout, channel: models.ChannelDiscord, payload: discordPayload}
+}
+
+func NewMattermostNotifier(c *http.Client, cfg config.ChatConfig) Notifier {
+	return &chatNotifier{Client: c, Urls: cfg.MattermostUrls, Timeout: cfg.Timeout, channel: models.ChannelMattermost, payload: mattermostPayload}
+}
+
+func (cn *chatNotifier) Channel() string {
+	return cn.channel
+}
+
+func (cn *chatNotifier) Recipient(channel models.UserChannel) (string, bool) {
+	return channel.Target, channel.Target != ""
+}
+
+func (cn *chatNotifier) ValidTarget(target string) bool {
+	return validUrl(target)
+}
+
+func (cn *chatNotifier) Global() []models.UserChannel {
+	channels := []models.UserChannel{}
+	for _, u := range cn.Urls {
+		channels = append(channels, models.UserChannel{Channel: cn.channel, Target: u, Enabled: true, Global: true})
+	}
+	return channels
+}
+
+func (cn *chatNotifier) Render(n Notification) (Message, error) {
+	switch n.Kind {
+	case KindReport, KindAlerts, KindBackInStock:
+	default:
+		return Message{}, errUnknownKind
+	}
+	body, err := json.Marshal(cn.payload(n))
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: n.Kind, Body: string(body)}, nil
+}
+
+func (cn *chatNotifier) Send(ctx context.Context, recipient string, msg Message) error {
+	return postJSON(ctx, cn.Client, cn.Timeout, recipient, msg.Body, nil)
+}
+
+type chatMarkup struct {
+	bold   func(s string) string
+	strike func(s string) string
+	link   func(text, url string) string
+}
+
+var (
+	slackMarkup = chatMarkup{
+		bold:   func(s string) string { return "*" + s + "*" },
+		strike: func(s string) string { return "~" + s + "~" },
+		link:   func(text, url string) string { return "<" + url + "|" + text + ">" },
+	}
+	markdownMarkup = chatMarkup{
+		bold:   func(s string) string { return "**" + s + "**" },
+		strike: func(s string) string { return "~~" + s + "~~" },
+		link:   func(text, url string) string { return "[" + text + "](" + url + ")" },
+	}
+)
+
+func iphoneDetails(iphone models.IPhone, m chatMarkup) string {
+	lines := []string{fmt.Sprintf("💰 %.2f byn | %s %s byn", iphone.Price, changeMark(iphone.Change), signedChange(iphone.Change))}
+	if iphone.Discount > 0 {
+		lines = append(lines, fmt.Sprintf("🔻 -%.0f%% от %s", iphone.Discount, m.strike(fmt.Sprintf("%.2f byn", iphone.OldPrice))))
+	}
+	if iphone.Installment > 0 {
+		lines = append(lines, fmt.Sprintf("💳 рассрочка от %.2f byn/мес", iphone.Installment))
+	}
+	if iphone.Availability != "" && iphone.Availability != models.AvailabilityInStock {
+		lines = append(lines, availabilityTitle(iphone.Availability))
+	}
+	if iphone.BestOffer != nil {
+		line := fmt.Sprintf("🏷 лучшая цена: %.2f byn в %s", iphone.BestOffer.Price, m.link(iphone.BestOffer.Source, iphone.BestOffer.Url))
+		if iphone.Spread > 0 {
+			line += fmt.Sprintf(" | ↔️ разброс: %.2f byn", iphone.Spread)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func alertDetails(f models.FiredAlert, m chatMarkup) string {
+	msg := fmt.Sprintf("%s (алерт #%d)", f.Reason, f.Alert.Id)
+	if f.IPhone.BestOffer != nil {
+		msg += fmt.Sprintf("\n🏷 купить за %.2f byn в %s", f.IPhone.BestOffer.Price, m.link(f.IPhone.BestOffer.Source, f.IPhone.BestOffer.Url))
+	}
+	return msg
+}
+
+func changeMark(change float64) string {
+	switch {
+	case change > 0:
+		return "📈"
+	case change < 0:
+		return "📉"
+	}
+	return "0️⃣"
+}
+
+func signedChange(change float64) string {
+	if change == 0 {
+		return "0.00"
+	}
+	return fmt.Sprintf("%+.2f", change)
+}
+
+func availabilityTitle(availability string) string {
+	switch availability {
+	case models.AvailabilityOutOfStock:
+		return "❌ нет в наличии"
+	case models.AvailabilityPreorder:
+		return "⏳ предзаказ"
+	}
+	return "✅ в наличии"
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Blocks      []slackBlock      `json:"blocks,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+func slackSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+// slackSections keeps room for the header and a "more" note within the block limit.
+func slackSections(texts []string) []slackBlock {
+	blocks := []slackBlock{}
+	limit := slackMaxBlocks - 2
+	for i, text := range texts {
+		if i == limit {
+			more := fmt.Sprintf("и еще %d", len(texts)-limit)
+			blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: more}}})
+			break
+		}
+		blocks = append(blocks, slackSection(text))
+	}
+	return blocks
+}
+
+func slackPayload(n Notification) any {
+	switch n.Kind {
+	case KindAlerts:
+		texts := []string{}
+		for _, f := range n.Alerts {
+			texts = append(texts, slackMarkup.bold(f.IPhone.Name)+"\n"+alertDetails(f, slackMarkup))
+		}
+		blocks := append([]slackBlock{slackSection(slackMarkup.bold(alertsTitle))}, slackSections(texts)...)
+		return slackMessage{Text: alertsTitle, Attachments: []slackAttachment{{Color: alertColor, Blocks: blocks}}}
+	case KindBackInStock:
+		iphone := n.IPhones[0]
+		title := fmt.Sprintf("🔥 %s снова в наличии!", iphone.Name)
+		blocks := []slackBlock{slackSection(slackMarkup.bold(title) + "\n" + iphoneDetails(iphone, slackMarkup))}
+		return slackMessage{Text: title, Attachments: []slackAttachment{{Color: stockColor, Blocks: blocks}}}
+	}
+	texts := []string{}
+	for _, iphone := range n.IPhones {
+		texts = append(texts, slackMarkup.bold(iphone.Name)+"\n"+iphoneDetails(iphone, slackMarkup))
+	}
+	header := slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: reportTitle}}
+	return slackMessage{Text: reportTitle, Blocks: append([]slackBlock{header}, slackSections(texts)...)}
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+func discordColor(hex string) int {
+	var color int
+	fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%x", &color)
+	return color
+}
+
+func discordEmbeds(title, color string, fields []discordField) []discordEmbed {
+	embeds := []discordEmbed{}
+	for start := 0; start < len(fields) && len(embeds) < discordMaxEmbeds; start += discordMaxFields {
+		embed := discordEmbed{Color: discordColor(color), Fields: fields[start:min(start+discordMaxFields, len(fields))]}
+		if start == 0 {
+			embed.Title = title
+		}
+		embeds = append(embeds, embed)
+	}
+	return embeds
+}
+
+func discordPayload(n Notification) any {
+	switch n.Kind {
+	case KindAlerts:
+		fields := []discordField{}
+		for _, f := range n.Alerts {
+			fields = append(fields, discordField{Name: truncate(f.IPhone.Name, discordFieldName), Value: truncate(alertDetails(f, markdownMarkup), discordFieldValue)})
+		}
+		return discordMessage{Content: alertsTitle, Embeds: discordEmbeds(alertsTitle, alertColor, fields)}
+	case KindBackInStock:
+		iphone := n.IPhones[0]
+		embed := discordEmbed{Title: fmt.Sprintf("🔥 %s снова в наличии!", iphone.Name), Description: iphoneDetails(iphone, markdownMarkup), Color: discordColor(stockColor)}
+		return discordMessage{Embeds: []discordEmbed{embed}}
+	}
+	fields := []discordField{}
+	for _, iphone := range n.IPhones {
+		fields = append(fields, discordField{Name: truncate(iphone.Name, discordFieldName), Value: truncate(iphoneDetails(iphone, markdownMarkup), discordFieldValue)})
+	}
+	return discordMessage{Embeds: discordEmbeds(reportTitle, infoColor, fields)}
+}
+
+type mattermostAttachment struct {
+	Fallback string `json:"fallback"`
+	Color    string `json:"color"`
+	Pretext  string `json:"pretext,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text"`
+}
+
+type mattermostMessage struct {
+	Text        string                 `json:"text,omitempty"`
+	Attachments []mattermostAttachment `json:"attachments,omitempty"`
+}
+
+func mattermostPayload(n Notification) any {
+	switch n.Kind {
+	case KindAlerts:
+		lines := []string{}
+		for _, f := range n.Alerts {
+			lines = append(lines, "- "+markdownMarkup.bold(f.IPhone.Name)+": "+strings.ReplaceAll(alertDetails(f, markdownMarkup), "\n", " "))
+		}
+		return mattermostMessage{Attachments: []mattermostAttachment{{Fallback: alertsTitle, Color: alertColor, Pretext: alertsTitle, Text: strings.Join(lines, "\n")}}}
+	case KindBackInStock:
+		iphone := n.IPhones[0]
+		title := fmt.Sprintf("🔥 %s снова в наличии!", iphone.Name)
+		return mattermostMessage{Attachments: []mattermostAttachment{{Fallback: title, Color: stockColor, Title: title, Text: iphoneDetails(iphone, markdownMarkup)}}}
+	}
+	rows := []string{
+		"#### " + reportTitle,
+		"| модель | цена | разница | скидка | наличие | лучшая цена |",
+		"|:--|--:|--:|--:|:--|:--|",
+	}
+	for _, iphone := range n.IPhones {
+		discount := ""
+		if iphone.Discount > 0 {
+			discount = fmt.Sprintf("-%.0f%% от %s", iphone.Discount, markdownMarkup.strike(fmt.Sprintf("%.2f", iphone.OldPrice)))
+		}
+		offer := ""
+		if iphone.BestOffer != nil {
+			offer = fmt.Sprintf("%.2f в %s", iphone.BestOffer.Price, markdownMarkup.link(iphone.BestOffer.Source, iphone.BestOffer.Url))
+		}
+		rows = append(rows, fmt.Sprintf("| %s | %.2f | %s %s | %s | %s | %s |", iphone.Name, iphone.Price, changeMark(iphone.Change), signedChange(iphone.Change), discount, availabilityTitle(iphone.Availability), offer))
+	}
+	return mattermostMessage{Text: strings.Join(rows, "\n")}
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chatIPhones(n int) []models.IPhone {
+	iphones := []models.IPhone{}
+	for i := range n {
+		iphones = append(iphones, models.IPhone{
+			Id:           fmt.Sprintf("iphone-%d-id", i),
+			Name:         fmt.Sprintf("iPhone 17 %d", i),
+			Price:        2750,
+			OldPrice:     3100,
+			Discount:     11.3,
+			Change:       -200,
+			Availability: models.AvailabilityInStock,
+			BestOffer:    &models.Offer{Source: "newton.by", Url: "https://newton.by/iphone", Price: 2750},
+		})
+	}
+	return iphones
+}
+
+func TestChatNotifiers_Render(t *testing.T) {
+	cfg := config.ChatConfig{SlackUrls: []string{"https://hooks.slack.com/services/T/B/X"}}
+	iphones := chatIPhones(60)
+	alerts := []models.FiredAlert{{Alert: models.Alert{Id: 7}, IPhone: iphones[0], Reason: "цена 2750.00 ниже 2800.00"}}
+
+	t.Run("slack block kit", func(t *testing.T) {
+		n := NewSlackNotifier(http.DefaultClient, cfg)
+		msg, err := n.Render(Notification{Kind: KindReport, IPhones: iphones})
+		assert.NoError(t, err)
+		var report slackMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &report))
+		assert.Len(t, report.Blocks, slackMaxBlocks)
+		assert.Equal(t, "header", report.Blocks[0].Type)
+		assert.Contains(t, report.Blocks[1].Text.Text, "~3100.00 byn~")
+		assert.Contains(t, report.Blocks[1].Text.Text, "<https://newton.by/iphone|newton.by>")
+		assert.Equal(t, "context", report.Blocks[len(report.Blocks)-1].Type)
+
+		msg, err = n.Render(Notification{Kind: KindAlerts, IPhones: iphones, Alerts: alerts})
+		assert.NoError(t, err)
+		var alert slackMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &alert))
+		assert.Equal(t, alertColor, alert.Attachments[0].Color)
+		assert.Contains(t, alert.Attachments[0].Blocks[1].Text.Text, "(алерт #7)")
+		assert.Equal(t, []models.UserChannel{{Channel: models.ChannelSlack, Target: cfg.SlackUrls[0], Enabled: true, Global: true}}, n.(GlobalNotifier).Global())
+	})
+
+	t.Run("discord embeds", func(t *testing.T) {
+		n := NewDiscordNotifier(http.DefaultClient, cfg)
+		msg, err := n.Render(Notification{Kind: KindReport, IPhones: iphones})
+		assert.NoError(t, err)
+		var report discordMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &report))
+		assert.Len(t, report.Embeds, 3)
+		assert.Len(t, report.Embeds[0].Fields, discordMaxFields)
+		assert.Equal(t, reportTitle, report.Embeds[0].Title)
+		assert.Empty(t, report.Embeds[1].Title)
+		assert.Contains(t, report.Embeds[0].Fields[0].Value, "[newton.by](https://newton.by/iphone)")
+
+		msg, err = n.Render(Notification{Kind: KindAlerts, Alerts: alerts})
+		assert.NoError(t, err)
+		var alert discordMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &alert))
+		assert.Equal(t, 0xc62828, alert.Embeds[0].Color)
+		assert.Equal(t, "iPhone 17 0", alert.Embeds[0].Fields[0].Name)
+	})
+
+	t.Run("mattermost table", func(t *testing.T) {
+		n := NewMattermostNotifier(http.DefaultClient, cfg)
+		msg, err := n.Render(Notification{Kind: KindReport, IPhones: iphones[:1]})
+		assert.NoError(t, err)
+		var report mattermostMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &report))
+		assert.Contains(t, report.Text, "| iPhone 17 0 | 2750.00 | 📉 -200.00 | -11% от ~~3100.00~~ | ✅ в наличии | 2750.00 в [newton.by](https://newton.by/iphone) |")
+
+		msg, err = n.Render(Notification{Kind: KindBackInStock, IPhones: iphones[:1]})
+		assert.NoError(t, err)
+		var stock mattermostMessage
+		assert.NoError(t, json.Unmarshal([]byte(msg.Body), &stock))
+		assert.Equal(t, stockColor, stock.Attachments[0].Color)
+		assert.Equal(t, "🔥 iPhone 17 0 снова в наличии!", stock.Attachments[0].Title)
+	})
+}
+
+func TestChatNotifiers_Send(t *testing.T) {
+	var received string
+	status := http.StatusOK
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+	n := NewDiscordNotifier(receiver.Client(), config.ChatConfig{})
+	msg, err := n.Render(Notification{Kind: KindReport, IPhones: chatIPhones(1)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, n.Send(context.Background(), receiver.URL, msg))
+	assert.JSONEq(t, msg.Body, received)
+
+	status = http.StatusTooManyRequests
+	assert.Error(t, n.Send(context.Background(), receiver.URL, msg))
+	assert.True(t, n.(TargetValidator).ValidTarget("https://discord.com/api/webhooks/1/abc"))
+	assert.False(t, n.(TargetValidator).ValidTarget("discord"))
+}
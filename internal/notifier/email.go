@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"iFall/internal/domain/models"
+	"iFall/internal/email"
+)
+
+const (
+	reportSubject = "цена говнофона семнадцатого 17"
+	alertsSubject = "сработали ваши алерты"
+)
+
+var errUnknownKind = errors.New("unknown notification kind")
+
+type emailNotifier struct {
+	EmailSender email.EmailSender
+}
+
+func NewEmailNotifier(es email.EmailSender) Notifier {
+	return &emailNotifier{
+		EmailSender: es,
+	}
+}
+
+func (en *emailNotifier) Channel() string {
+	return models.ChannelEmail
+}
+
+func (en *emailNotifier) Recipient(channel models.UserChannel) (string, bool) {
+	return channel.Email, channel.Email != ""
+}
+
+func (en *emailNotifier) Render(n Notification) (Message, error) {
+	switch n.Kind {
+	case KindReport:
+		content, err := email.BuildEmailLetter(n.IPhones)
+		return Message{Subject: reportSubject, Body: content}, err
+	case KindAlerts:
+		content, err := email.BuildAlertsLetter(n.Alerts)
+		return Message{Subject: alertsSubject, Body: content}, err
+	case KindBackInStock:
+		content, err := email.BuildBackInStockLetter(n.IPhones[0])
+		return Message{Subject: n.IPhones[0].Name + " снова в наличии", Body: content}, err
+	}
+	return Message{}, errUnknownKind
+}
+
+func (en *emailNotifier) Send(ctx context.Context, recipient string, msg Message) error {
+	return en.EmailSender.SendMessage(ctx, msg.Subject, []byte(msg.Body), []string{recipient}, nil)
+}
@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notifier.go
+
+// Package mock_notifier is a generated GoMock package.
+package mock_notifier
+
+import (
+	context "context"
+	models "iFall/internal/domain/models"
+	notifier "iFall/internal/notifier"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Channel mocks base method.
+func (m *MockNotifier) Channel() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Channel")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Channel indicates an expected call of Channel.
+func (mr *MockNotifierMockRecorder) Channel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Channel", reflect.TypeOf((*MockNotifier)(nil).Channel))
+}
+
+// Recipient mocks base method.
+func (m *MockNotifier) Recipient(channel models.UserChannel) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recipient", channel)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Recipient indicates an expected call of Recipient.
+func (mr *MockNotifierMockRecorder) Recipient(channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recipient", reflect.TypeOf((*MockNotifier)(nil).Recipient), channel)
+}
+
+// Render mocks base method.
+func (m *MockNotifier) Render(n notifier.Notification) (notifier.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", n)
+	ret0, _ := ret[0].(notifier.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockNotifierMockRecorder) Render(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockNotifier)(nil).Render), n)
+}
+
+// Send mocks base method.
+func (m *MockNotifier) Send(ctx context.Context, recipient string, msg notifier.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, recipient, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockNotifierMockRecorder) Send(ctx, recipient, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockNotifier)(nil).Send), ctx, recipient, msg)
+}
+
+// MockGlobalNotifier is a mock of GlobalNotifier interface.
+type MockGlobalNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockGlobalNotifierMockRecorder
+}
+
+// MockGlobalNotifierMockRecorder is the mock recorder for MockGlobalNotifier.
+type MockGlobalNotifierMockRecorder struct {
+	mock *MockGlobalNotifier
+}
+
+// NewMockGlobalNotifier creates a new mock instance.
+func NewMockGlobalNotifier(ctrl *gomock.Controller) *MockGlobalNotifier {
+	mock := &MockGlobalNotifier{ctrl: ctrl}
+	mock.recorder = &MockGlobalNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGlobalNotifier) EXPECT() *MockGlobalNotifierMockRecorder {
+	return m.recorder
+}
+
+// Global mocks base method.
+func (m *MockGlobalNotifier) Global() []models.UserChannel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Global")
+	ret0, _ := ret[0].([]models.UserChannel)
+	return ret0
+}
+
+// Global indicates an expected call of Global.
+func (mr *MockGlobalNotifierMockRecorder) Global() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Global", reflect.TypeOf((*MockGlobalNotifier)(nil).Global))
+}
+
+// MockTargetValidator is a mock of TargetValidator interface.
+type MockTargetValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockTargetValidatorMockRecorder
+}
+
+// MockTargetValidatorMockRecorder is the mock recorder for MockTargetValidator.
+type MockTargetValidatorMockRecorder struct {
+	mock *MockTargetValidator
+}
+
+// NewMockTargetValidator creates a new mock instance.
+func NewMockTargetValidator(ctrl *gomock.Controller) *MockTargetValidator {
+	mock := &MockTargetValidator{ctrl: ctrl}
+	mock.recorder = &MockTargetValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTargetValidator) EXPECT() *MockTargetValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidTarget mocks base method.
+func (m *MockTargetValidator) ValidTarget(target string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidTarget", target)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ValidTarget indicates an expected call of ValidTarget.
+func (mr *MockTargetValidatorMockRecorder) ValidTarget(target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidTarget", reflect.TypeOf((*MockTargetValidator)(nil).ValidTarget), target)
+}
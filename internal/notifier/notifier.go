@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"iFall/internal/domain/models"
+)
+
+const (
+	KindReport      = "report"
+	KindAlerts      = "alerts"
+	KindBackInStock = "back_in_stock"
+)
+
+type Notification struct {
+	Kind    string
+	IPhones []models.IPhone
+	Alerts  []models.FiredAlert
+}
+
+type Message struct {
+	Subject string
+	Body    string
+}
+
+//go:generate mockgen -source=notifier.go -destination=mocks/notifier-mock.go
+type Notifier interface {
+	Channel() string
+	Recipient(channel models.UserChannel) (string, bool)
+	Render(n Notification) (Message, error)
+	Send(ctx context.Context, recipient string, msg Message) error
+}
+
+// GlobalNotifier is implemented by channels that also deliver to recipients configured for the whole app.
+type GlobalNotifier interface {
+	Global() []models.UserChannel
+}
+
+// TargetValidator is implemented by channels whose users provide their own target, like a webhook url.
+type TargetValidator interface {
+	ValidTarget(target string) bool
+}
+
+type Registry struct {
+	notifiers map[string]Notifier
+	channels  []string
+}
+
+func NewRegistry(notifiers ...Notifier) *Registry {
+	r := &Registry{notifiers: map[string]Notifier{}}
+	for _, n := range notifiers {
+		r.Register(n)
+	}
+	return r
+}
+
+func (r *Registry) Register(n Notifier) {
+	if _, ok := r.notifiers[n.Channel()]; !ok {
+		r.channels = append(r.channels, n.Channel())
+	}
+	r.notifiers[n.Channel()] = n
+}
+
+func (r *Registry) Get(channel string) (Notifier, bool) {
+	n, ok := r.notifiers[channel]
+	return n, ok
+}
+
+func (r *Registry) Channels() []string {
+	return r.channels
+}
+
+func (r *Registry) Global() []models.UserChannel {
+	channels := []models.UserChannel{}
+	for _, channel := range r.channels {
+		if g, ok := r.notifiers[channel].(GlobalNotifier); ok {
+			channels = append(channels, g.Global()...)
+		}
+	}
+	return channels
+}
+
+func (r *Registry) ValidTarget(channel, target string) bool {
+	n, ok := r.notifiers[channel]
+	if !ok {
+		return false
+	}
+	if v, ok := n.(TargetValidator); ok {
+		return v.ValidTarget(target)
+	}
+	return true
+}
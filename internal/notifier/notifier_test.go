@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	mock_bot "iFall/internal/bot/mocks"
+	"iFall/internal/domain/models"
+	mock_email "iFall/internal/email/mocks"
+	"iFall/internal/utils"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	registry := NewRegistry(NewEmailNotifier(mock_email.NewMockEmailSender(c)), NewTelegramNotifier(mock_bot.NewMockTelegramBot(c)))
+	registry.Register(NewEmailNotifier(mock_email.NewMockEmailSender(c)))
+	assert.Equal(t, []string{models.ChannelEmail, models.ChannelTelegram}, registry.Channels())
+	_, ok := registry.Get(models.ChannelTelegram)
+	assert.True(t, ok)
+	_, ok = registry.Get("pigeon")
+	assert.False(t, ok)
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	botMock := mock_bot.NewMockTelegramBot(c)
+	n := NewTelegramNotifier(botMock)
+
+	_, ok := n.Recipient(models.UserChannel{Channel: models.ChannelTelegram, Contacts: models.Contacts{Email: "sanya@gmail.com"}})
+	assert.False(t, ok)
+	recipient, ok := n.Recipient(models.UserChannel{Channel: models.ChannelTelegram, Contacts: models.Contacts{ChatId: utils.Int64ToPtr(111)}})
+	assert.True(t, ok)
+	assert.Equal(t, "111", recipient)
+
+	msg, err := n.Render(Notification{Kind: KindBackInStock, IPhones: []models.IPhone{{Name: "iPhone 17", Price: 2750}}})
+	assert.NoError(t, err)
+	assert.Contains(t, msg.Body, "iPhone 17 снова в наличии")
+	_, err = n.Render(Notification{Kind: "vibes"})
+	assert.ErrorIs(t, err, errUnknownKind)
+
+	botMock.EXPECT().SendMessage(int64(111), msg.Body).Return(nil)
+	assert.NoError(t, n.Send(context.Background(), recipient, msg))
+	assert.Error(t, n.Send(context.Background(), "not-a-chat", msg))
+}
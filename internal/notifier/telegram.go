@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"iFall/internal/bot"
+	"iFall/internal/domain/models"
+	"strconv"
+)
+
+type telegramNotifier struct {
+	Bot bot.TelegramBot
+}
+
+func NewTelegramNotifier(b bot.TelegramBot) Notifier {
+	return &telegramNotifier{
+		Bot: b,
+	}
+}
+
+func (tn *telegramNotifier) Channel() string {
+	return models.ChannelTelegram
+}
+
+func (tn *telegramNotifier) Recipient(channel models.UserChannel) (string, bool) {
+	if channel.ChatId == nil {
+		return "", false
+	}
+	return strconv.FormatInt(*channel.ChatId, 10), true
+}
+
+func (tn *telegramNotifier) Render(n Notification) (Message, error) {
+	switch n.Kind {
+	case KindReport:
+		return Message{Body: bot.IPhonesInfoMessage(n.IPhones)}, nil
+	case KindAlerts:
+		return Message{Body: bot.FiredAlertsMessage(n.Alerts)}, nil
+	case KindBackInStock:
+		return Message{Body: bot.BackInStockMessage(n.IPhones[0])}, nil
+	}
+	return Message{}, errUnknownKind
+}
+
+func (tn *telegramNotifier) Send(ctx context.Context, recipient string, msg Message) error {
+	chatId, err := strconv.ParseInt(recipient, 10, 64)
+	if err != nil {
+		return err
+	}
+	return tn.Bot.SendMessage(chatId, msg.Body)
+}
@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	webhookEventHeader     = "X-IFall-Event"
+	webhookTimestampHeader = "X-IFall-Timestamp"
+	webhookSignatureHeader = "X-IFall-Signature"
+)
+
+type webhookPayload struct {
+	Event   string              `json:"event"`
+	IPhones []models.IPhone     `json:"iphones"`
+	Alerts  []models.FiredAlert `json:"alerts,omitempty"`
+}
+
+type webhookNotifier struct {
+	Client        *http.Client
+	WebhookConfig config.WebhookConfig
+}
+
+var errWebhookSecret = errors.New("webhook urls are configured without a secret")
+
+// WebhookEnabled reports whether the webhook channel can be served, without a secret receivers could not tell our payloads from forged ones.
+func WebhookEnabled(cfg config.WebhookConfig) (bool, error) {
+	if cfg.Secret != "" {
+		return true, nil
+	}
+	if len(cfg.Urls) > 0 {
+		return false, errWebhookSecret
+	}
+	return false, nil
+}
+
+func NewWebhookNotifier(c *http.Client, cfg config.WebhookConfig) Notifier {
+	return &webhookNotifier{
+		Client:        c,
+		WebhookConfig: cfg,
+	}
+}
+
+func (wn *webhookNotifier) Channel() string {
+	return models.ChannelWebhook
+}
+
+func (wn *webhookNotifier) Recipient(channel models.UserChannel) (string, bool) {
+	return channel.Target, channel.Target != ""
+}
+
+func (wn *webhookNotifier) ValidTarget(target string) bool {
+	return validUrl(target)
+}
+
+func (wn *webhookNotifier) Global() []models.UserChannel {
+	channels := []models.UserChannel{}
+	for _, u := range wn.WebhookConfig.Urls {
+		channels = append(channels, models.UserChannel{Channel: models.ChannelWebhook, Target: u, Enabled: true, Global: true})
+	}
+	return channels
+}
+
+func (wn *webhookNotifier) Render(n Notification) (Message, error) {
+	switch n.Kind {
+	case KindReport, KindAlerts, KindBackInStock:
+	default:
+		return Message{}, errUnknownKind
+	}
+	body, err := json.Marshal(webhookPayload{Event: n.Kind, IPhones: n.IPhones, Alerts: n.Alerts})
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: n.Kind, Body: string(body)}, nil
+}
+
+func (wn *webhookNotifier) Send(ctx context.Context, recipient string, msg Message) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		webhookEventHeader:     msg.Subject,
+		webhookTimestampHeader: timestamp,
+		webhookSignatureHeader: "sha256=" + WebhookSignature(wn.WebhookConfig.Secret, timestamp, []byte(msg.Body)),
+	}
+	return postJSON(ctx, wn.Client, wn.WebhookConfig.Timeout, recipient, msg.Body, headers)
+}
+
+// WebhookSignature signs "<timestamp>.<body>" so receivers can reject replayed requests.
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var errPrivateAddress = errors.New("refusing to connect to a non-public address")
+
+// validUrl rejects targets pointing at the host's own network, names resolving there
+// are caught when dialing by NewPublicClient.
+func validUrl(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return publicAddr(ip)
+	}
+	return true
+}
+
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() && ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, IsPrivate does not cover it.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewPublicClient returns a client for user supplied urls, every dial is checked
+// against the resolved ip so dns tricks and redirects can't reach internal services.
+func NewPublicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddr(addrPort.Addr()) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would dial on our behalf and skip the check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
+
+func postJSON(ctx context.Context, c *http.Client, timeout time.Duration, target, body string, headers map[string]string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"iFall/internal/config"
+	"iFall/internal/domain/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	secret := "test-secret"
+	status := http.StatusOK
+	var received webhookPayload
+	var headers http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+		signature := "sha256=" + WebhookSignature(secret, r.Header.Get(webhookTimestampHeader), body)
+		if r.Header.Get(webhookSignatureHeader) != signature {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &received)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	n := NewWebhookNotifier(receiver.Client(), config.WebhookConfig{Secret: secret, Timeout: time.Second})
+	iphone := models.IPhone{Id: "iphone-green-id", Name: "iPhone 17 256GB Green", Price: 2750}
+	msg, err := n.Render(Notification{
+		Kind:    KindAlerts,
+		IPhones: []models.IPhone{iphone},
+		Alerts:  []models.FiredAlert{{Alert: models.Alert{Id: 1, Kind: models.AlertKindPrice, Threshold: 2800}, IPhone: iphone, Reason: "цена 2750.00 ниже 2800.00"}},
+	})
+	assert.NoError(t, err)
+
+	t.Run("success signed delivery", func(t *testing.T) {
+		assert.NoError(t, n.Send(context.Background(), receiver.URL, msg))
+		assert.Equal(t, KindAlerts, headers.Get(webhookEventHeader))
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+		assert.NotEmpty(t, headers.Get(webhookTimestampHeader))
+		assert.Equal(t, KindAlerts, received.Event)
+		assert.Equal(t, "iphone-green-id", received.IPhones[0].Id)
+		assert.Equal(t, int64(1), received.Alerts[0].Alert.Id)
+		assert.Equal(t, "цена 2750.00 ниже 2800.00", received.Alerts[0].Reason)
+	})
+
+	t.Run("failed with wrong secret", func(t *testing.T) {
+		wrong := NewWebhookNotifier(receiver.Client(), config.WebhookConfig{Secret: "wrong"})
+		assert.Error(t, wrong.Send(context.Background(), receiver.URL, msg))
+	})
+
+	t.Run("failed with non 2xx", func(t *testing.T) {
+		status = http.StatusBadGateway
+		defer func() { status = http.StatusOK }()
+		assert.Error(t, n.Send(context.Background(), receiver.URL, msg))
+	})
+}
+
+func TestWebhookNotifier_Targets(t *testing.T) {
+	n := NewWebhookNotifier(http.DefaultClient, config.WebhookConfig{Secret: "test-secret", Urls: []string{"https://hooks.local/ifall"}})
+	registry := NewRegistry(n)
+	assert.True(t, registry.ValidTarget(models.ChannelWebhook, "https://hooks.local/ifall"))
+	assert.False(t, registry.ValidTarget(models.ChannelWebhook, "ftp://hooks.local"))
+	assert.False(t, registry.ValidTarget(models.ChannelWebhook, "hooks.local"))
+	assert.False(t, registry.ValidTarget("pigeon", "roof"))
+	assert.Equal(t, []models.UserChannel{{Channel: models.ChannelWebhook, Target: "https://hooks.local/ifall", Enabled: true, Global: true}}, registry.Global())
+	recipient, ok := n.Recipient(models.UserChannel{Channel: models.ChannelWebhook, Target: "https://example.com/hook"})
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/hook", recipient)
+}
+
+func TestWebhookEnabled(t *testing.T) {
+	tests := []struct {
+		testName      string
+		cfg           config.WebhookConfig
+		expected      bool
+		expectedError error
+	}{
+		{
+			testName: "enabled with secret",
+			cfg:      config.WebhookConfig{Secret: "test-secret"},
+			expected: true,
+		},
+		{
+			testName: "disabled without secret and urls",
+			cfg:      config.WebhookConfig{},
+			expected: false,
+		},
+		{
+			testName:      "failed with urls but no secret",
+			cfg:           config.WebhookConfig{Urls: []string{"https://hooks.local/ifall"}},
+			expectedError: errWebhookSecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			enabled, err := WebhookEnabled(tt.cfg)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expected, enabled)
+		})
+	}
+}
+
+func TestValidUrl(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected bool
+	}{
+		{target: "https://hooks.example.com/ifall", expected: true},
+		{target: "http://93.184.216.34:8080/hook", expected: true},
+		{target: "ftp://hooks.example.com", expected: false},
+		{target: "hooks.example.com", expected: false},
+		{target: "http://localhost:8080/hook", expected: false},
+		{target: "http://api.localhost/hook", expected: false},
+		{target: "http://127.0.0.1/hook", expected: false},
+		{target: "http://10.0.0.5/hook", expected: false},
+		{target: "http://172.16.0.1/hook", expected: false},
+		{target: "http://192.168.1.1/hook", expected: false},
+		{target: "http://169.254.169.254/latest/meta-data", expected: false},
+		{target: "http://100.64.0.1/hook", expected: false},
+		{target: "http://0.0.0.0/hook", expected: false},
+		{target: "http://[::1]/hook", expected: false},
+		{target: "http://[::ffff:127.0.0.1]/hook", expected: false},
+		{target: "http://[fe80::1]/hook", expected: false},
+		{target: "http://[fd00::1]/hook", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validUrl(tt.target))
+		})
+	}
+}
+
+func TestNewPublicClient(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	err := postJSON(context.Background(), NewPublicClient(), time.Second, receiver.URL, "{}", nil)
+	assert.ErrorIs(t, err, errPrivateAddress)
+	assert.NoError(t, postJSON(context.Background(), receiver.Client(), time.Second, receiver.URL, "{}", nil))
+}
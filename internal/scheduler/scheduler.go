@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"iFall/internal/config"
 	"iFall/internal/domain/services"
@@ -14,10 +15,12 @@ type Scheduler struct {
 	Logger              *logger.Logger
 	IPhoneService       services.IPhoneService
 	IPhoneReportService services.IphoneReportService
+	OutboxService       services.OutboxService
 	SchedulerConfig     config.SchedulerConfig
+	OutboxConfig        config.OutboxConfig
 }
 
-func NewScheduler(is services.IPhoneService, irs services.IphoneReportService, l *logger.Logger, scfg config.SchedulerConfig) *Scheduler {
+func NewScheduler(is services.IPhoneService, irs services.IphoneReportService, obs services.OutboxService, l *logger.Logger, scfg config.SchedulerConfig, ocfg config.OutboxConfig) *Scheduler {
 	cr := cron.New(cron.WithChain(
 		cron.SkipIfStillRunning(cron.DefaultLogger),
 	))
@@ -25,8 +28,10 @@ func NewScheduler(is services.IPhoneService, irs services.IphoneReportService, l
 		Cron:                cr,
 		IPhoneService:       is,
 		IPhoneReportService: irs,
+		OutboxService:       obs,
 		Logger:              l,
 		SchedulerConfig:     scfg,
+		OutboxConfig:        ocfg,
 	}
 }
 
@@ -41,10 +46,22 @@ func (s *Scheduler) Start() {
 			if err := s.IPhoneReportService.SendIPhonesInfo(s.SchedulerConfig.EmailSupp, iphones); err != nil {
 				log.Error("failed to send iphones info", logger.Err(err))
 			}
+			if err := s.IPhoneReportService.SendBackInStock(s.SchedulerConfig.EmailSupp, iphones); err != nil {
+				log.Error("failed to send back in stock notifications", logger.Err(err))
+			}
 		}
 	}); err != nil {
 		panic(fmt.Errorf("failed to start IphonesPriceChecking: %w", err))
 	}
+	if _, err := s.Cron.AddFunc(fmt.Sprintf("@every %s", s.OutboxConfig.Interval), func() {
+		op := "scheduler.OutboxDelivering"
+		log := s.Logger.AddOp(op)
+		if err := s.OutboxService.Deliver(context.Background()); err != nil {
+			log.Error("failed to deliver outbox", logger.Err(err))
+		}
+	}); err != nil {
+		panic(fmt.Errorf("failed to start OutboxDelivering: %w", err))
+	}
 	s.Cron.Start()
 }
 
@@ -1,5 +1,12 @@
 package utils
 
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
 func StrToPtr(s string) *string {
 	return &s
 }
@@ -7,3 +14,15 @@ func StrToPtr(s string) *string {
 func Int64ToPtr(i int64) *int64 {
 	return &i
 }
+
+// NewApiToken returns a random token, only its HashApiToken is stored.
+func NewApiToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func HashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}